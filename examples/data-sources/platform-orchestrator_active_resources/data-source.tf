@@ -0,0 +1,10 @@
+# The Postgres databases currently provisioned in the staging environment.
+data "platform-orchestrator_active_resources" "postgres" {
+  project_id    = "my-project"
+  env_id        = "staging"
+  resource_type = "postgres"
+}
+
+output "postgres_module_versions" {
+  value = { for r in data.platform-orchestrator_active_resources.postgres.resources : r.id => "${r.module_id}@${r.module_version}" }
+}
@@ -0,0 +1,12 @@
+# The latest successful deployment to an environment.
+data "platform-orchestrator_deployment" "latest" {
+  project_id = "my-project"
+  env_id     = "staging"
+}
+
+# Promote the manifest of the latest successful staging deployment to production.
+resource "platform-orchestrator_deployment" "production" {
+  project_id = "my-project"
+  env_id     = "production"
+  manifest   = data.platform-orchestrator_deployment.latest.manifest
+}
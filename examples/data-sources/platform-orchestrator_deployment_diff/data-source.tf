@@ -0,0 +1,9 @@
+# The changes of the last deployment to an environment compared to the previous one.
+data "platform-orchestrator_deployment_diff" "last" {
+  project_id = "my-project"
+  env_id     = "production"
+}
+
+output "removed_resources" {
+  value = [for change in data.platform-orchestrator_deployment_diff.last.changes : change.resource if change.type == "removed"]
+}
@@ -0,0 +1,16 @@
+variable "logs_identity" {
+  description = "The age identity the deployment logs were encrypted for."
+  type        = string
+  sensitive   = true
+}
+
+data "platform-orchestrator_deployment_logs" "errors" {
+  deployment_id = "01234567-89ab-cdef-0123-456789abcdef"
+  identity      = var.logs_identity
+  level         = "error"
+}
+
+resource "local_file" "error_logs" {
+  filename = "${path.module}/deployment-errors.log"
+  content  = join("\n", [for line in data.platform-orchestrator_deployment_logs.errors.lines : "${coalesce(line.timestamp, "-")} ${line.message}"])
+}
@@ -0,0 +1,10 @@
+data "platform-orchestrator_deployment_tf" "main" {
+  deployment_id = platform-orchestrator_deployment.main.id
+}
+
+# Write the compiled configuration to disk to review what the Platform Orchestrator ran.
+resource "local_file" "deployment_tf" {
+  for_each = data.platform-orchestrator_deployment_tf.main.files
+  filename = "${path.module}/deployment-tf/${each.key}"
+  content  = each.value
+}
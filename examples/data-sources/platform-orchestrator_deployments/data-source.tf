@@ -0,0 +1,7 @@
+# The last 10 successful deployments to the development environment of a project.
+data "platform-orchestrator_deployments" "succeeded" {
+  project_id  = "my-project"
+  env_id      = "development"
+  status      = "succeeded"
+  max_results = 10
+}
@@ -0,0 +1,14 @@
+# All active production environments in the organization.
+data "platform-orchestrator_environments" "production" {
+  env_type_ids = ["production"]
+  status       = "active"
+}
+
+# The environments of a single project.
+data "platform-orchestrator_environments" "my_project" {
+  project_id = "my-project"
+}
+
+output "production_environments" {
+  value = [for env in data.platform-orchestrator_environments.production.environments : "${env.project_id}/${env.id}"]
+}
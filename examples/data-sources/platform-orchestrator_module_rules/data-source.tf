@@ -0,0 +1,14 @@
+# All module rules for the postgres resource type.
+data "platform-orchestrator_module_rules" "postgres" {
+  resource_type = "postgres"
+}
+
+# The module rules set to the production environment type.
+data "platform-orchestrator_module_rules" "production" {
+  env_type_id = "production"
+}
+
+# The default rules, which apply to every project and environment.
+output "default_postgres_rules" {
+  value = [for r in data.platform-orchestrator_module_rules.postgres.rules : r.id if r.project_id == null && r.env_type_id == null && r.env_id == null]
+}
@@ -0,0 +1,13 @@
+# All runner rules in the organization.
+data "platform-orchestrator_runner_rules" "all" {
+}
+
+# The runner rules pointing at a single runner.
+data "platform-orchestrator_runner_rules" "my_runner" {
+  runner_id = "my-runner"
+}
+
+# The runner rules set to the production environment type.
+data "platform-orchestrator_runner_rules" "production" {
+  env_type_id = "production"
+}
@@ -0,0 +1,13 @@
+# All runners in the organization.
+data "platform-orchestrator_runners" "all" {
+}
+
+# Only the EKS runners.
+data "platform-orchestrator_runners" "eks" {
+  runner_type = "kubernetes-eks"
+}
+
+# Runners which do not keep their state in S3 or GCS.
+output "runners_without_remote_state" {
+  value = [for r in data.platform-orchestrator_runners.all.runners : r.id if !contains(["s3", "gcs"], r.state_storage_type)]
+}
@@ -0,0 +1 @@
+terraform import platform-orchestrator_deployment.main "01234567-89ab-cdef-0123-456789abcdef"
@@ -8,3 +8,30 @@ resource "platform-orchestrator_deployment" "main" {
   })
   wait_for = true
 }
+
+# The manifest can also be described with structured attributes, which are validated at plan time.
+resource "platform-orchestrator_deployment" "structured" {
+  project_id          = "my-project"
+  env_id              = "staging"
+  on_destroy          = "rollback"
+  rollback_on_failure = true
+  workloads = {
+    main = {
+      resources = {
+        db = {
+          type   = "postgres"
+          params = jsonencode({ version = "16" })
+        }
+      }
+      outputs = {
+        db_host = "$${resources.db.outputs.host}"
+      }
+    }
+  }
+  shared = {
+    dns = {
+      type = "dns"
+      id   = "shared.dns"
+    }
+  }
+}
@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+)
+
+var _ datasource.DataSource = &ActiveResourcesDataSource{}
+
+func NewActiveResourcesDataSource() datasource.DataSource {
+	return &ActiveResourcesDataSource{}
+}
+
+type ActiveResourcesDataSource struct {
+	dpClient canyondp.ClientWithResponsesInterface
+	orgId    string
+}
+
+type ActiveResourcesDataSourceModel struct {
+	ProjectId     types.String `tfsdk:"project_id"`
+	EnvId         types.String `tfsdk:"env_id"`
+	ResourceType  types.String `tfsdk:"resource_type"`
+	ResourceClass types.String `tfsdk:"resource_class"`
+	Resources     types.List   `tfsdk:"resources"`
+}
+
+// ActiveResourceModel describes an active node of the resource graph of an environment.
+type ActiveResourceModel struct {
+	Id            types.String         `tfsdk:"id"`
+	DeploymentId  types.String         `tfsdk:"deployment_id"`
+	ResourceType  types.String         `tfsdk:"resource_type"`
+	ResourceClass types.String         `tfsdk:"resource_class"`
+	ResourceId    types.String         `tfsdk:"resource_id"`
+	ModuleId      types.String         `tfsdk:"module_id"`
+	ModuleVersion types.String         `tfsdk:"module_version"`
+	Metadata      jsontypes.Normalized `tfsdk:"metadata"`
+	Edges         types.Map            `tfsdk:"edges"`
+}
+
+func activeResourceAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":             types.StringType,
+		"deployment_id":  types.StringType,
+		"resource_type":  types.StringType,
+		"resource_class": types.StringType,
+		"resource_id":    types.StringType,
+		"module_id":      types.StringType,
+		"module_version": types.StringType,
+		"metadata":       jsontypes.NormalizedType{},
+		"edges":          types.MapType{ElemType: types.StringType},
+	}
+}
+
+func (d *ActiveResourcesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_active_resources"
+}
+
+func (d *ActiveResourcesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Active resources data source. Lists the nodes of the resource graph that are currently provisioned in an environment.",
+
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the project.",
+				Required:            true,
+			},
+			"env_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the environment.",
+				Required:            true,
+			},
+			"resource_type": schema.StringAttribute{
+				MarkdownDescription: "Only return resources of this resource type.",
+				Optional:            true,
+			},
+			"resource_class": schema.StringAttribute{
+				MarkdownDescription: "Only return resources of this resource class.",
+				Optional:            true,
+			},
+			"resources": schema.ListNestedAttribute{
+				MarkdownDescription: "The list of active resources.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The deterministic hash of the node in the resource graph, as used in `edges`.",
+							Computed:            true,
+						},
+						"deployment_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the deployment that last provisioned the resource.",
+							Computed:            true,
+						},
+						"resource_type": schema.StringAttribute{
+							MarkdownDescription: "The resource type of the resource.",
+							Computed:            true,
+						},
+						"resource_class": schema.StringAttribute{
+							MarkdownDescription: "The resource class of the resource.",
+							Computed:            true,
+						},
+						"resource_id": schema.StringAttribute{
+							MarkdownDescription: "The resource id of the resource.",
+							Computed:            true,
+						},
+						"module_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the module that provisioned the resource.",
+							Computed:            true,
+						},
+						"module_version": schema.StringAttribute{
+							MarkdownDescription: "The version of the module that provisioned the resource.",
+							Computed:            true,
+						},
+						"metadata": schema.StringAttribute{
+							MarkdownDescription: "The JSON encoded metadata produced by the resource.",
+							Computed:            true,
+							CustomType:          jsontypes.NormalizedType{},
+						},
+						"edges": schema.MapAttribute{
+							MarkdownDescription: "The dependency edges of the node in the resource graph, referencing other nodes by their `id`.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ActiveResourcesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*HumanitecProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			HUM_PROVIDER_ERR,
+			fmt.Sprintf("Expected *HumanitecProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.dpClient = providerData.DpClient
+	d.orgId = providerData.OrgId
+}
+
+func toActiveResourceModel(ctx context.Context, node canyondp.ActiveResourceNode) (ActiveResourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	model := ActiveResourceModel{
+		Id:            types.StringValue(node.Id),
+		DeploymentId:  types.StringValue(node.DeploymentId.String()),
+		ResourceType:  types.StringValue(node.ResourceType),
+		ResourceClass: types.StringValue(node.ResourceClass),
+		ResourceId:    types.StringValue(node.ResourceId),
+		ModuleId:      types.StringValue(node.ModuleId),
+		ModuleVersion: types.StringValue(node.ModuleVersion),
+		Metadata:      jsontypes.NewNormalizedNull(),
+	}
+	if node.Metadata != nil {
+		metadata, err := json.Marshal(node.Metadata)
+		if err != nil {
+			diags.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Failed to serialize metadata of resource node %s: %s", node.Id, err))
+			return model, diags
+		}
+		model.Metadata = jsontypes.NewNormalizedValue(string(metadata))
+	}
+	edges := node.Edges
+	if edges == nil {
+		edges = map[string]string{}
+	}
+	model.Edges, diags = types.MapValueFrom(ctx, types.StringType, edges)
+	return model, diags
+}
+
+func (d *ActiveResourcesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ActiveResourcesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	httpResp, err := d.dpClient.ListActiveResourceNodesWithResponse(ctx, d.orgId, &canyondp.ListActiveResourceNodesParams{
+		ProjectId: data.ProjectId.ValueStringPointer(),
+		EnvId:     data.EnvId.ValueStringPointer(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to list active resources, got error: %s", err))
+		return
+	}
+	if httpResp.StatusCode() != http.StatusOK {
+		resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to list active resources, unexpected status code: %d, body: %s", httpResp.StatusCode(), httpResp.Body))
+		return
+	}
+
+	resourceAttributeTypes := activeResourceAttributeTypes()
+
+	items := make([]attr.Value, 0, len(httpResp.JSON200.Items))
+	for _, node := range httpResp.JSON200.Items {
+		if !data.ResourceType.IsNull() && node.ResourceType != data.ResourceType.ValueString() {
+			continue
+		} else if !data.ResourceClass.IsNull() && node.ResourceClass != data.ResourceClass.ValueString() {
+			continue
+		}
+
+		model, diags := toActiveResourceModel(ctx, node)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if rm, diags := types.ObjectValueFrom(ctx, resourceAttributeTypes, model); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		} else {
+			items = append(items, rm)
+		}
+	}
+
+	itemsValue, diags := types.ListValue(types.ObjectType{AttrTypes: resourceAttributeTypes}, items)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	data.Resources = itemsValue
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
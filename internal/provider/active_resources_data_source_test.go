@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccActiveResourcesDataSource(t *testing.T) {
+	suffix := strings.ToLower(rand.Text())
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A new environment has no active resources yet
+			{
+				Config: fmt.Sprintf(`
+resource "platform-orchestrator_project" "project" {
+  id = "project-%[1]s"
+}
+
+resource "platform-orchestrator_environment_type" "env_type" {
+  id = "env-type-%[1]s"
+}
+
+resource "platform-orchestrator_environment" "env" {
+  id          = "env-%[1]s"
+  project_id  = platform-orchestrator_project.project.id
+  env_type_id = platform-orchestrator_environment_type.env_type.id
+}
+
+data "platform-orchestrator_active_resources" "postgres" {
+  project_id    = platform-orchestrator_environment.env.project_id
+  env_id        = platform-orchestrator_environment.env.id
+  resource_type = "postgres"
+}
+`, suffix),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.platform-orchestrator_active_resources.postgres",
+						tfjsonpath.New("resources"),
+						knownvalue.ListExact([]knownvalue.Check{}),
+					),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+)
+
+var _ datasource.DataSource = &DeploymentDataSource{}
+
+func NewDeploymentDataSource() datasource.DataSource {
+	return &DeploymentDataSource{}
+}
+
+type DeploymentDataSource struct {
+	dpClient canyondp.ClientWithResponsesInterface
+	orgId    string
+}
+
+type DeploymentDataSourceModel struct {
+	Id                     types.String `tfsdk:"id"`
+	ProjectId              types.String `tfsdk:"project_id"`
+	EnvId                  types.String `tfsdk:"env_id"`
+	Status                 types.String `tfsdk:"status"`
+	StatusMessage          types.String `tfsdk:"status_message"`
+	Mode                   types.String `tfsdk:"mode"`
+	RollbackToDeploymentId types.String `tfsdk:"rollback_to_deployment_id"`
+	RunnerId               types.String `tfsdk:"runner_id"`
+	CreatedBy              types.String `tfsdk:"created_by"`
+	CreatedAt              types.String `tfsdk:"created_at"`
+	CompletedAt            types.String `tfsdk:"completed_at"`
+	Manifest               types.String `tfsdk:"manifest"`
+	Workloads              types.Map    `tfsdk:"workloads"`
+	Shared                 types.Map    `tfsdk:"shared"`
+	Metrics                types.Object `tfsdk:"metrics"`
+}
+
+func (d *DeploymentDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment"
+}
+
+func deploymentManifestResourceDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"type": schema.StringAttribute{
+			MarkdownDescription: "The resource type to provision.",
+			Computed:            true,
+		},
+		"class": schema.StringAttribute{
+			MarkdownDescription: "A resource class requested by the resource graph.",
+			Computed:            true,
+		},
+		"id": schema.StringAttribute{
+			MarkdownDescription: "A specific resource id requested by the resource graph.",
+			Computed:            true,
+		},
+		"params": schema.StringAttribute{
+			MarkdownDescription: "A JSON encoded object of parameters to pass to the resource provisioning.",
+			Computed:            true,
+			CustomType:          jsontypes.NormalizedType{},
+		},
+	}
+}
+
+func (d *DeploymentDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deployment data source. Looks up a deployment by ID, or the latest successful deployment to an environment.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the deployment. Exactly one of `id` or `env_id` must be set.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("env_id")),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the project of the deployment. Set together with `env_id` to look up the latest successful deployment to the environment.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("env_id")),
+				},
+			},
+			"env_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the environment of the deployment. Set together with `project_id` to look up the latest successful deployment to the environment.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("project_id")),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The status of the deployment.",
+				Computed:            true,
+			},
+			"status_message": schema.StringAttribute{
+				MarkdownDescription: "The message associated with the status of the deployment.",
+				Computed:            true,
+			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "The mode of the deployment, for example 'deploy', 'plan_only', or 'rollback'.",
+				Computed:            true,
+			},
+			"rollback_to_deployment_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the deployment this deployment rolled back to, if it is a rollback.",
+				Computed:            true,
+			},
+			"runner_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the runner that executed the deployment.",
+				Computed:            true,
+			},
+			"created_by": schema.StringAttribute{
+				MarkdownDescription: "The ID of the user that created the deployment.",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The Created At timestamp of the deployment in RFC3339 format.",
+				Computed:            true,
+			},
+			"completed_at": schema.StringAttribute{
+				MarkdownDescription: "The Completed At timestamp of the deployment in RFC3339 format, if it has completed.",
+				Computed:            true,
+			},
+			"manifest": schema.StringAttribute{
+				MarkdownDescription: "The YAML encoded manifest of the deployment.",
+				Computed:            true,
+			},
+			"workloads": schema.MapNestedAttribute{
+				MarkdownDescription: "The workloads of the manifest, keyed by workload name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"resources": schema.MapNestedAttribute{
+							MarkdownDescription: "The resources of the workload, keyed by resource name.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: deploymentManifestResourceDataSourceAttributes(),
+							},
+						},
+						"variables": schema.MapAttribute{
+							MarkdownDescription: "The variables of the workload. Deprecated by the Platform Orchestrator, use `outputs` instead.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"outputs": schema.MapAttribute{
+							MarkdownDescription: "The outputs of the workload.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+			"shared": schema.MapNestedAttribute{
+				MarkdownDescription: "The shared resources of the manifest, keyed by resource name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: deploymentManifestResourceDataSourceAttributes(),
+				},
+			},
+			"metrics": deploymentMetricsDataSourceAttribute(),
+		},
+	}
+}
+
+func (d *DeploymentDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*HumanitecProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			HUM_PROVIDER_ERR,
+			fmt.Sprintf("Expected *HumanitecProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.dpClient = providerData.DpClient
+	d.orgId = providerData.OrgId
+}
+
+func (d *DeploymentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DeploymentDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var deploymentUuid uuid.UUID
+	if !data.Id.IsNull() {
+		id, err := uuid.Parse(data.Id.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("id"), HUM_INPUT_ERR, fmt.Sprintf("The deployment ID must be a UUID, got error: %s", err))
+			return
+		}
+		deploymentUuid = id
+	} else {
+		last, err := fetchLastSucceededDeployment(ctx, d.dpClient, d.orgId, data.ProjectId.ValueString(), data.EnvId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to find the latest successful deployment, %s", err))
+			return
+		} else if last == nil {
+			resp.Diagnostics.AddError(HUM_RESOURCE_NOT_FOUND_ERR, fmt.Sprintf("No successful deployment to environment %s in project %s found", data.EnvId.ValueString(), data.ProjectId.ValueString()))
+			return
+		}
+		deploymentUuid = last.Id
+	}
+
+	httpResp, err := d.dpClient.GetDeploymentWithResponse(ctx, d.orgId, deploymentUuid)
+	if err != nil {
+		resp.Diagnostics.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to read deployment, got error: %s", err))
+		return
+	}
+
+	if httpResp.StatusCode() == http.StatusNotFound {
+		resp.Diagnostics.AddError(HUM_RESOURCE_NOT_FOUND_ERR, fmt.Sprintf("Deployment with ID %s not found in org %s", deploymentUuid, d.orgId))
+		return
+	}
+
+	if httpResp.StatusCode() != http.StatusOK {
+		resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to read deployment, unexpected status code: %d, body: %s", httpResp.StatusCode(), httpResp.Body))
+		return
+	}
+
+	deployment := httpResp.JSON200
+	manifest, err := manifestToYaml(deployment.Manifest)
+	if err != nil {
+		resp.Diagnostics.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to serialize deployment manifest, got error: %s", err))
+		return
+	}
+
+	data.Id = types.StringValue(deployment.Id.String())
+	data.ProjectId = types.StringValue(deployment.ProjectId)
+	data.EnvId = types.StringValue(deployment.EnvId)
+	data.Status = types.StringValue(deployment.Status)
+	data.StatusMessage = types.StringValue(deployment.StatusMessage)
+	data.Mode = types.StringValue(deploymentMode(deployment.Mode, deployment.PlanOnly))
+	data.RollbackToDeploymentId = types.StringNull()
+	if deployment.RollbackToDeploymentId != nil {
+		data.RollbackToDeploymentId = types.StringValue(deployment.RollbackToDeploymentId.String())
+	}
+	data.RunnerId = types.StringValue(deployment.RunnerId)
+	data.CreatedBy = types.StringValue(deployment.CreatedBy.String())
+	data.CreatedAt = types.StringValue(deployment.CreatedAt.Format(time.RFC3339))
+	data.CompletedAt = types.StringNull()
+	if deployment.CompletedAt != nil {
+		data.CompletedAt = types.StringValue(deployment.CompletedAt.Format(time.RFC3339))
+	}
+	data.Manifest = types.StringValue(manifest)
+
+	workloads, diags := toDeploymentWorkloadsValue(ctx, deployment.Manifest.Workloads)
+	resp.Diagnostics.Append(diags...)
+	shared, diags := toDeploymentManifestResourcesValue(ctx, deployment.Manifest.Shared)
+	resp.Diagnostics.Append(diags...)
+	metrics, diags := toDeploymentMetricsValue(ctx, deployment.Metrics)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Workloads = workloads
+	data.Shared = shared
+	data.Metrics = metrics
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
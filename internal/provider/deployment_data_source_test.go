@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDeploymentDataSource_no_succeeded_deployment(t *testing.T) {
+	suffix := strings.ToLower(rand.Text())
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "platform-orchestrator_project" "project" {
+  id = "project-%[1]s"
+}
+
+resource "platform-orchestrator_environment_type" "env_type" {
+  id = "env-type-%[1]s"
+}
+
+resource "platform-orchestrator_environment" "env" {
+  id          = "env-%[1]s"
+  project_id  = platform-orchestrator_project.project.id
+  env_type_id = platform-orchestrator_environment_type.env_type.id
+}
+
+data "platform-orchestrator_deployment" "latest" {
+  project_id = platform-orchestrator_environment.env.project_id
+  env_id     = platform-orchestrator_environment.env.id
+}
+`, suffix),
+				ExpectError: regexp.MustCompile(`No successful deployment to environment env-.* found`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentDataSource_not_found(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "platform-orchestrator_deployment" "deployment" {
+  id = "00000000-0000-0000-0000-000000000000"
+}
+`, ExpectError: regexp.MustCompile(`Deployment with ID 00000000-0000-0000-0000-000000000000 not found`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentDataSource_id_conflicts_with_env_id(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "platform-orchestrator_deployment" "deployment" {
+  id         = "00000000-0000-0000-0000-000000000000"
+  project_id = "my-project"
+  env_id     = "development"
+}
+`, ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+}
@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+)
+
+var _ datasource.DataSource = &DeploymentDiffDataSource{}
+
+func NewDeploymentDiffDataSource() datasource.DataSource {
+	return &DeploymentDiffDataSource{}
+}
+
+type DeploymentDiffDataSource struct {
+	dpClient canyondp.ClientWithResponsesInterface
+	orgId    string
+}
+
+type DeploymentDiffDataSourceModel struct {
+	ProjectId        types.String `tfsdk:"project_id"`
+	EnvId            types.String `tfsdk:"env_id"`
+	FromDeploymentId types.String `tfsdk:"from_deployment_id"`
+	ToDeploymentId   types.String `tfsdk:"to_deployment_id"`
+	NumAdded         types.Int64  `tfsdk:"num_added"`
+	NumChanged       types.Int64  `tfsdk:"num_changed"`
+	NumRemoved       types.Int64  `tfsdk:"num_removed"`
+	Changes          types.List   `tfsdk:"changes"`
+}
+
+func (d *DeploymentDiffDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment_diff"
+}
+
+func (d *DeploymentDiffDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deployment diff data source. Describes the changes to the resource graph between two deployments to the same environment.",
+
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the project. Set together with `env_id` to diff the last deployment that changed the environment.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("env_id")),
+				},
+			},
+			"env_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the environment. Set together with `project_id` to diff the last deployment that changed the environment.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("project_id")),
+				},
+			},
+			"to_deployment_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the deployment to calculate the diff to. Exactly one of `to_deployment_id` or `env_id` must be set.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("env_id")),
+				},
+			},
+			"from_deployment_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of an earlier deployment to the same environment to calculate the diff from. Defaults to the previous deployment that changed the environment, and is null if there is none.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"num_added": schema.Int64Attribute{
+				MarkdownDescription: "The number of resources added.",
+				Computed:            true,
+			},
+			"num_changed": schema.Int64Attribute{
+				MarkdownDescription: "The number of resources changed.",
+				Computed:            true,
+			},
+			"num_removed": schema.Int64Attribute{
+				MarkdownDescription: "The number of resources removed.",
+				Computed:            true,
+			},
+			"changes": schema.ListNestedAttribute{
+				MarkdownDescription: "The changes to the nodes of the resource graph.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The deterministic hash of the resource node.",
+							Computed:            true,
+						},
+						"resource": schema.StringAttribute{
+							MarkdownDescription: "The resource identifier of the node, including the type, class, and id.",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The type of change: 'added', 'removed', 'params_changed', or 'module_changed'.",
+							Computed:            true,
+						},
+						"summary": schema.StringAttribute{
+							MarkdownDescription: "A human readable summary of the change.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *DeploymentDiffDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*HumanitecProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			HUM_PROVIDER_ERR,
+			fmt.Sprintf("Expected *HumanitecProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.dpClient = providerData.DpClient
+	d.orgId = providerData.OrgId
+}
+
+func (d *DeploymentDiffDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DeploymentDiffDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var toUuid uuid.UUID
+	if !data.ToDeploymentId.IsNull() {
+		id, err := uuid.Parse(data.ToDeploymentId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("to_deployment_id"), HUM_INPUT_ERR, fmt.Sprintf("The deployment ID must be a UUID, got error: %s", err))
+			return
+		}
+		toUuid = id
+	} else {
+		last, err := fetchLastDeployment(ctx, d.dpClient, d.orgId, data.ProjectId.ValueString(), data.EnvId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to find the last deployment, %s", err))
+			return
+		} else if last == nil {
+			resp.Diagnostics.AddError(HUM_RESOURCE_NOT_FOUND_ERR, fmt.Sprintf("No deployment to environment %s in project %s found", data.EnvId.ValueString(), data.ProjectId.ValueString()))
+			return
+		}
+		toUuid = last.Id
+	}
+
+	params := &canyondp.CalculateDeploymentDiffParams{}
+	if !data.FromDeploymentId.IsNull() {
+		id, err := uuid.Parse(data.FromDeploymentId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("from_deployment_id"), HUM_INPUT_ERR, fmt.Sprintf("The deployment ID must be a UUID, got error: %s", err))
+			return
+		}
+		params.FromDeploymentId = &id
+	}
+
+	httpResp, err := d.dpClient.CalculateDeploymentDiffWithResponse(ctx, d.orgId, toUuid, params)
+	if err != nil {
+		resp.Diagnostics.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to calculate deployment diff, got error: %s", err))
+		return
+	}
+
+	if httpResp.StatusCode() == http.StatusNotFound {
+		resp.Diagnostics.AddError(HUM_RESOURCE_NOT_FOUND_ERR, fmt.Sprintf("Deployment with ID %s not found in org %s", toUuid, d.orgId))
+		return
+	}
+
+	if httpResp.StatusCode() != http.StatusOK {
+		resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to calculate deployment diff, unexpected status code: %d, body: %s", httpResp.StatusCode(), httpResp.Body))
+		return
+	}
+
+	diff := httpResp.JSON200
+	changes, diags := toDeploymentDiffChangesValue(ctx, diff.Changes)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ToDeploymentId = types.StringValue(toUuid.String())
+	data.FromDeploymentId = types.StringNull()
+	if diff.FromDeploymentId != nil {
+		data.FromDeploymentId = types.StringValue(diff.FromDeploymentId.String())
+	}
+	// Fill in the environment when the diff was requested by deployment ID.
+	if data.ProjectId.IsNull() {
+		if r, err := d.dpClient.GetDeploymentWithResponse(ctx, d.orgId, toUuid); err != nil {
+			resp.Diagnostics.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to read deployment, got error: %s", err))
+			return
+		} else if r.StatusCode() != http.StatusOK {
+			resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to read deployment, unexpected status code: %d, body: %s", r.StatusCode(), r.Body))
+			return
+		} else {
+			data.ProjectId = types.StringValue(r.JSON200.ProjectId)
+			data.EnvId = types.StringValue(r.JSON200.EnvId)
+		}
+	}
+	data.NumAdded = types.Int64Value(int64(diff.NumAdded))
+	data.NumChanged = types.Int64Value(int64(diff.NumChanged))
+	data.NumRemoved = types.Int64Value(int64(diff.NumRemoved))
+	data.Changes = changes
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
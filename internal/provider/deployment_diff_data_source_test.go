@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDeploymentDiffDataSource_not_found(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "platform-orchestrator_deployment_diff" "diff" {
+  to_deployment_id = "00000000-0000-0000-0000-000000000000"
+}
+`, ExpectError: regexp.MustCompile(`Deployment with ID 00000000-0000-0000-0000-000000000000 not found`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentDiffDataSource_invalid_id(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "platform-orchestrator_deployment_diff" "diff" {
+  to_deployment_id   = "00000000-0000-0000-0000-000000000000"
+  from_deployment_id = "not-a-uuid"
+}
+`, ExpectError: regexp.MustCompile(`The deployment ID must be a UUID`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentDiffDataSource_requires_deployment_or_env(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "platform-orchestrator_deployment_diff" "diff" {
+  from_deployment_id = "00000000-0000-0000-0000-000000000000"
+}
+`, ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+}
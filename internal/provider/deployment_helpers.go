@@ -0,0 +1,307 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+	"terraform-provider-humanitec-v2/internal/ref"
+)
+
+// deploymentOutputsKeyPrivateStateKey is the private state key holding the age identity that the outputs and logs of
+// the current deployment are encrypted with.
+const deploymentOutputsKeyPrivateStateKey = "outputs_key"
+
+// deploymentCreateFailedPrivateStateKey is the private state key marking a resource whose create failed, which Terraform
+// taints and replaces on the next apply.
+const deploymentCreateFailedPrivateStateKey = "create_failed"
+
+// privateStateReader is implemented by the private state of the framework requests.
+type privateStateReader interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// privateStateWriter is implemented by the private state of the framework responses.
+type privateStateWriter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// setDeploymentOutputsKey stores the outputs key of the deployment in the private state. A nil key removes the key of
+// an earlier deployment, for a deployment whose outputs key is not known.
+func setDeploymentOutputsKey(ctx context.Context, private privateStateWriter, key *age.X25519Identity) diag.Diagnostics {
+	if key == nil {
+		// Setting an empty value removes the key.
+		return private.SetKey(ctx, deploymentOutputsKeyPrivateStateKey, nil)
+	}
+	// Private state values must be valid JSON.
+	raw, _ := json.Marshal(key.String())
+	return private.SetKey(ctx, deploymentOutputsKeyPrivateStateKey, raw)
+}
+
+// getDeploymentOutputsKey reads the outputs key of the deployment from the private state. It returns nil if no key
+// is stored, for example for imported deployments.
+func getDeploymentOutputsKey(ctx context.Context, private privateStateReader) (*age.X25519Identity, diag.Diagnostics) {
+	raw, diags := private.GetKey(ctx, deploymentOutputsKeyPrivateStateKey)
+	if diags.HasError() || len(raw) == 0 {
+		return nil, diags
+	}
+
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		diags.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to read the deployment outputs key from the private state, got error: %s", err))
+		return nil, diags
+	}
+	key, err := age.ParseX25519Identity(encoded)
+	if err != nil {
+		diags.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to parse the deployment outputs key from the private state, got error: %s", err))
+		return nil, diags
+	}
+	return key, diags
+}
+
+// setDeploymentCreateFailed marks in the private state whether the create of the resource failed.
+func setDeploymentCreateFailed(ctx context.Context, private privateStateWriter, failed bool) diag.Diagnostics {
+	if !failed {
+		// Setting an empty value removes the key.
+		return private.SetKey(ctx, deploymentCreateFailedPrivateStateKey, nil)
+	}
+	return private.SetKey(ctx, deploymentCreateFailedPrivateStateKey, []byte("true"))
+}
+
+// getDeploymentCreateFailed returns true if the private state marks the create of the resource as failed.
+func getDeploymentCreateFailed(ctx context.Context, private privateStateReader) (bool, diag.Diagnostics) {
+	raw, diags := private.GetKey(ctx, deploymentCreateFailedPrivateStateKey)
+	return string(raw) == "true", diags
+}
+
+// deploymentIdempotencyKey derives the idempotency key of a deployment request from the organization, a nonce telling
+// apart deployments of the same request body, and the request body. It must be called before the encryption recipients
+// are set, since they are different for every attempt.
+func deploymentIdempotencyKey(orgId, nonce string, body canyondp.DeploymentCreateBody) (string, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	hash.Write([]byte(orgId + "\n" + nonce + "\n"))
+	hash.Write(raw)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// fetchDeploymentOutputs returns the JSON encoded outputs of a succeeded deployment, decrypted with the key whose
+// recipient was set as the outputs recipient when the deployment was created.
+func fetchDeploymentOutputs(ctx context.Context, client canyondp.ClientWithResponsesInterface, orgId string, deploymentId uuid.UUID, key *age.X25519Identity) (string, error) {
+	r, err := client.GetDeploymentEncryptedOutputsWithResponse(ctx, orgId, deploymentId)
+	if err != nil {
+		return "", fmt.Errorf("unable to read deployment outputs, got error: %w", err)
+	} else if r.StatusCode() != http.StatusOK {
+		return "", fmt.Errorf("unable to read deployment outputs, unexpected status code: %d, body: %s", r.StatusCode(), r.Body)
+	}
+
+	decrypted, err := age.Decrypt(base64.NewDecoder(base64.StdEncoding, strings.NewReader(r.JSON200.Raw)), key)
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt deployment outputs, got error: %w", err)
+	}
+	raw, err := io.ReadAll(decrypted)
+	if err != nil {
+		return "", fmt.Errorf("unable to read decrypted deployment outputs, got error: %w", err)
+	}
+	return string(raw), nil
+}
+
+// fetchLastDeployment returns the last deployment that changed the state of the environment, or nil if there is none.
+func fetchLastDeployment(ctx context.Context, client canyondp.ClientWithResponsesInterface, orgId, projectId, envId string) (*canyondp.DeploymentSummary, error) {
+	r, err := client.ListLastDeploymentsWithResponse(ctx, orgId, &canyondp.ListLastDeploymentsParams{
+		ProjectId:       &projectId,
+		EnvId:           &envId,
+		StateChangeOnly: ref.Ref(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list last deployments, got error: %w", err)
+	} else if r.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unable to list last deployments, unexpected status code: %d, body: %s", r.StatusCode(), r.Body)
+	}
+
+	for _, item := range r.JSON200.Items {
+		if item.ProjectId == projectId && item.EnvId == envId {
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+
+// fetchLastSucceededDeployment returns the most recent successful deployment that changed the state of the
+// environment, or nil if there is none.
+func fetchLastSucceededDeployment(ctx context.Context, client canyondp.ClientWithResponsesInterface, orgId, projectId, envId string) (*canyondp.DeploymentSummary, error) {
+	last, err := fetchLastDeployment(ctx, client, orgId, projectId, envId)
+	if err != nil || last == nil || last.Status == "succeeded" {
+		return last, err
+	}
+
+	// The last deployment did not succeed, so look further back in the history of the environment.
+	var latest *canyondp.DeploymentSummary
+	var pageCursor *string
+	for {
+		r, err := client.ListDeploymentsWithResponse(ctx, orgId, &canyondp.ListDeploymentsParams{
+			ProjectId: &projectId,
+			EnvId:     &envId,
+			Page:      pageCursor,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list deployments, got error: %w", err)
+		} else if r.StatusCode() != http.StatusOK {
+			return nil, fmt.Errorf("unable to list deployments, unexpected status code: %d, body: %s", r.StatusCode(), r.Body)
+		}
+
+		for _, item := range r.JSON200.Items {
+			if item.Status != "succeeded" || item.PlanOnly {
+				continue
+			}
+			if latest == nil || item.CreatedAt.After(latest.CreatedAt) {
+				latest = &item
+			}
+		}
+
+		if r.JSON200.NextPageToken == nil {
+			break
+		}
+		pageCursor = r.JSON200.NextPageToken
+	}
+	return latest, nil
+}
+
+// deploymentMode returns the mode of a deployment, with plan only deployments reported as 'plan_only'.
+func deploymentMode(mode string, planOnly bool) string {
+	if planOnly {
+		return string(canyondp.PlanOnly)
+	}
+	return mode
+}
+
+// DeploymentMetricsModel describes the metrics resulting from a deployment.
+type DeploymentMetricsModel struct {
+	NumWorkloads          types.Int64 `tfsdk:"num_workloads"`
+	NumResourceNodes      types.Int64 `tfsdk:"num_resource_nodes"`
+	NumTfResources        types.Int64 `tfsdk:"num_tf_resources"`
+	NumTfResourcesAdded   types.Int64 `tfsdk:"num_tf_resources_added"`
+	NumTfResourcesChanged types.Int64 `tfsdk:"num_tf_resources_changed"`
+	NumTfResourcesRemoved types.Int64 `tfsdk:"num_tf_resources_removed"`
+}
+
+func deploymentMetricsAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"num_workloads":            types.Int64Type,
+		"num_resource_nodes":       types.Int64Type,
+		"num_tf_resources":         types.Int64Type,
+		"num_tf_resources_added":   types.Int64Type,
+		"num_tf_resources_changed": types.Int64Type,
+		"num_tf_resources_removed": types.Int64Type,
+	}
+}
+
+// toDeploymentMetricsValue converts the API deployment metrics into a DeploymentMetricsModel object.
+func toDeploymentMetricsValue(ctx context.Context, metrics canyondp.DeploymentMetrics) (types.Object, diag.Diagnostics) {
+	return types.ObjectValueFrom(ctx, deploymentMetricsAttributeTypes(), DeploymentMetricsModel{
+		NumWorkloads:          types.Int64Value(int64(metrics.NumWorkloads)),
+		NumResourceNodes:      types.Int64Value(int64(metrics.NumResourceNodes)),
+		NumTfResources:        toInt64ValueOrNil(metrics.NumTfResources),
+		NumTfResourcesAdded:   toInt64ValueOrNil(metrics.NumTfResourcesAdded),
+		NumTfResourcesChanged: toInt64ValueOrNil(metrics.NumTfResourcesChanged),
+		NumTfResourcesRemoved: toInt64ValueOrNil(metrics.NumTfResourcesRemoved),
+	})
+}
+
+// DeploymentDiffChangeModel describes a single change to a node of the resource graph.
+type DeploymentDiffChangeModel struct {
+	Id       types.String `tfsdk:"id"`
+	Resource types.String `tfsdk:"resource"`
+	Type     types.String `tfsdk:"type"`
+	Summary  types.String `tfsdk:"summary"`
+}
+
+func deploymentDiffChangeAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":       types.StringType,
+		"resource": types.StringType,
+		"type":     types.StringType,
+		"summary":  types.StringType,
+	}
+}
+
+// toDeploymentDiffChangesValue converts the API diff changes into a list of DeploymentDiffChangeModel objects.
+func toDeploymentDiffChangesValue(ctx context.Context, changes []canyondp.DeploymentDiffChange) (types.List, diag.Diagnostics) {
+	items := make([]DeploymentDiffChangeModel, 0, len(changes))
+	for _, change := range changes {
+		items = append(items, DeploymentDiffChangeModel{
+			Id:       types.StringValue(change.Id),
+			Resource: types.StringValue(change.Resource),
+			Type:     types.StringValue(string(change.Type)),
+			Summary:  types.StringValue(change.Summary),
+		})
+	}
+	return types.ListValueFrom(ctx, types.ObjectType{AttrTypes: deploymentDiffChangeAttributeTypes()}, items)
+}
+
+// fetchEncryptedDeploymentLogs returns the runner logs of a deployment, decrypted locally with the given identity, so
+// that the identity is never sent to the API.
+func fetchEncryptedDeploymentLogs(ctx context.Context, client canyondp.ClientWithResponsesInterface, orgId string, deploymentId uuid.UUID, identity age.Identity) (string, error) {
+	r, err := client.GetDeploymentLogsWithResponse(ctx, orgId, deploymentId, &canyondp.GetDeploymentLogsParams{})
+	if err != nil {
+		return "", fmt.Errorf("unable to read deployment logs, got error: %w", err)
+	} else if r.StatusCode() != http.StatusOK {
+		return "", fmt.Errorf("unable to read deployment logs, unexpected status code: %d, body: %s", r.StatusCode(), r.Body)
+	}
+	return decryptDeploymentLogs(r.Body, identity)
+}
+
+// decryptDeploymentLogs decrypts age encrypted logs in the binary, armored, or base64 encoded format.
+func decryptDeploymentLogs(encrypted []byte, identity age.Identity) (string, error) {
+	var in io.Reader
+	switch trimmed := bytes.TrimSpace(encrypted); {
+	case len(trimmed) == 0:
+		return "", nil
+	case bytes.HasPrefix(trimmed, []byte(armor.Header)):
+		in = armor.NewReader(bytes.NewReader(trimmed))
+	case bytes.HasPrefix(trimmed, []byte("age-encryption.org/")):
+		in = bytes.NewReader(encrypted)
+	default:
+		in = base64.NewDecoder(base64.StdEncoding, bytes.NewReader(trimmed))
+	}
+
+	decrypted, err := age.Decrypt(in, identity)
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt deployment logs, got error: %w", err)
+	}
+	raw, err := io.ReadAll(decrypted)
+	if err != nil {
+		return "", fmt.Errorf("unable to read decrypted deployment logs, got error: %w", err)
+	}
+	return string(raw), nil
+}
+
+// tailLines returns at most the last n lines of the given text, ignoring trailing line breaks.
+func tailLines(text string, n int) []string {
+	lines := strings.Split(strings.TrimRight(text, "\r\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+)
+
+func TestTailLines(t *testing.T) {
+	assert.Nil(t, tailLines("", 5))
+	assert.Nil(t, tailLines("\n\n", 5))
+	assert.Equal(t, []string{"a", "b"}, tailLines("a\nb\n", 5))
+	assert.Equal(t, []string{"c", "d"}, tailLines("a\nb\nc\nd\r\n", 2))
+}
+
+type testPrivateState map[string][]byte
+
+func (s testPrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return s[key], nil
+}
+
+func (s testPrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	s[key] = value
+	return nil
+}
+
+func TestDeploymentOutputsKey(t *testing.T) {
+	private := testPrivateState{}
+
+	key, diags := getDeploymentOutputsKey(context.Background(), private)
+	assert.False(t, diags.HasError())
+	assert.Nil(t, key)
+
+	expected, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	assert.False(t, setDeploymentOutputsKey(context.Background(), private, expected).HasError())
+	assert.True(t, json.Valid(private[deploymentOutputsKeyPrivateStateKey]))
+
+	key, diags = getDeploymentOutputsKey(context.Background(), private)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, expected.String(), key.String())
+}
+
+func TestDeploymentIdempotencyKey(t *testing.T) {
+	body := canyondp.DeploymentCreateBody{
+		ProjectId: "my-project",
+		EnvId:     "development",
+		Mode:      canyondp.Deploy,
+		Manifest:  &canyondp.DeploymentManifest{Workloads: map[string]canyondp.DeploymentManifestWorkload{"main": {}}},
+	}
+	key, err := deploymentIdempotencyKey("my-org", "", body)
+	require.NoError(t, err)
+
+	again, err := deploymentIdempotencyKey("my-org", "", body)
+	require.NoError(t, err)
+	assert.Equal(t, key, again)
+
+	other, err := deploymentIdempotencyKey("my-org", "01234567-89ab-cdef-0123-456789abcdef", body)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	body.Mode = canyondp.PlanOnly
+	changed, err := deploymentIdempotencyKey("my-org", "", body)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, changed)
+}
+
+func TestDecryptDeploymentLogs(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	encrypt := func(w io.Writer) {
+		out, err := age.Encrypt(w, identity.Recipient())
+		require.NoError(t, err)
+		_, err = out.Write([]byte("hello\n"))
+		require.NoError(t, err)
+		require.NoError(t, out.Close())
+	}
+
+	var binary bytes.Buffer
+	encrypt(&binary)
+
+	var armored bytes.Buffer
+	armorWriter := armor.NewWriter(&armored)
+	encrypt(armorWriter)
+	require.NoError(t, armorWriter.Close())
+
+	encoded := []byte(base64.StdEncoding.EncodeToString(binary.Bytes()))
+
+	for name, encrypted := range map[string][]byte{"binary": binary.Bytes(), "armored": armored.Bytes(), "base64": encoded} {
+		t.Run(name, func(t *testing.T) {
+			logs, err := decryptDeploymentLogs(encrypted, identity)
+			require.NoError(t, err)
+			assert.Equal(t, "hello\n", logs)
+		})
+	}
+
+	other, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	_, err = decryptDeploymentLogs(binary.Bytes(), other)
+	var noIdentityMatch *age.NoIdentityMatchError
+	assert.ErrorAs(t, err, &noIdentityMatch)
+}
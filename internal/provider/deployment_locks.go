@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DeploymentLocks serializes the deployments made by the provider to the same environment, while deployments to
+// different environments still run in parallel.
+type DeploymentLocks struct {
+	mutex sync.Mutex
+	locks map[string]chan struct{}
+}
+
+func NewDeploymentLocks() *DeploymentLocks {
+	return &DeploymentLocks{locks: make(map[string]chan struct{})}
+}
+
+func (l *DeploymentLocks) lockFor(projectId, envId string) chan struct{} {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	key := projectId + "/" + envId
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		l.locks[key] = lock
+	}
+	return lock
+}
+
+// Lock blocks until no other deployment to the environment holds the lock, or returns the context error when the
+// context is done first. The returned function releases the lock.
+func (l *DeploymentLocks) Lock(ctx context.Context, projectId, envId string) (func(), error) {
+	lock := l.lockFor(projectId, envId)
+	unlock := func() {
+		<-lock
+	}
+
+	select {
+	case lock <- struct{}{}:
+		return unlock, nil
+	default:
+	}
+
+	tflog.Info(ctx, "Waiting for another deployment to the environment to complete...", map[string]interface{}{"project_id": projectId, "env_id": envId})
+	select {
+	case lock <- struct{}{}:
+		return unlock, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
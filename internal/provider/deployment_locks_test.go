@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentLocks(t *testing.T) {
+	locks := NewDeploymentLocks()
+
+	unlock, err := locks.Lock(context.Background(), "my-project", "development")
+	require.NoError(t, err)
+
+	// Other environments are not blocked.
+	unlockOther, err := locks.Lock(context.Background(), "my-project", "production")
+	require.NoError(t, err)
+	unlockOther()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = locks.Lock(ctx, "my-project", "development")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+	unlock, err = locks.Lock(context.Background(), "my-project", "development")
+	require.NoError(t, err)
+	unlock()
+}
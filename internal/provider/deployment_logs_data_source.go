@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+	"terraform-provider-humanitec-v2/internal/ref"
+)
+
+var _ datasource.DataSource = &DeploymentLogsDataSource{}
+
+func NewDeploymentLogsDataSource() datasource.DataSource {
+	return &DeploymentLogsDataSource{}
+}
+
+type DeploymentLogsDataSource struct {
+	dpClient canyondp.ClientWithResponsesInterface
+	orgId    string
+}
+
+type DeploymentLogsDataSourceModel struct {
+	DeploymentId types.String `tfsdk:"deployment_id"`
+	Identity     types.String `tfsdk:"identity"`
+	Level        types.String `tfsdk:"level"`
+	Contains     types.String `tfsdk:"contains"`
+	Lines        types.List   `tfsdk:"lines"`
+}
+
+// DeploymentLogLineModel describes a single line of the runner logs of a deployment.
+type DeploymentLogLineModel struct {
+	Timestamp types.String `tfsdk:"timestamp"`
+	Level     types.String `tfsdk:"level"`
+	Message   types.String `tfsdk:"message"`
+}
+
+func deploymentLogLineAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"timestamp": types.StringType,
+		"level":     types.StringType,
+		"message":   types.StringType,
+	}
+}
+
+func (d *DeploymentLogsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment_logs"
+}
+
+func (d *DeploymentLogsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deployment logs data source. Returns the runner logs of a deployment, which are kept for 30 days. The logs are decrypted by the provider, the identity is never sent to the API.",
+
+		Attributes: map[string]schema.Attribute{
+			"deployment_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the deployment.",
+				Required:            true,
+			},
+			"identity": schema.StringAttribute{
+				MarkdownDescription: "The age identity (`AGE-SECRET-KEY-1...`) whose recipient was set as the logs recipient when the deployment was created.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"level": schema.StringAttribute{
+				MarkdownDescription: "Only return lines with this log level, for example 'error'. The comparison is case-insensitive.",
+				Optional:            true,
+			},
+			"contains": schema.StringAttribute{
+				MarkdownDescription: "Only return lines whose message contains this substring.",
+				Optional:            true,
+			},
+			"lines": schema.ListNestedAttribute{
+				MarkdownDescription: "The lines of the logs.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"timestamp": schema.StringAttribute{
+							MarkdownDescription: "The timestamp of the line in RFC3339 format, if the line has one.",
+							Computed:            true,
+						},
+						"level": schema.StringAttribute{
+							MarkdownDescription: "The lowercase log level of the line, if the line has one.",
+							Computed:            true,
+						},
+						"message": schema.StringAttribute{
+							MarkdownDescription: "The message of the line.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *DeploymentLogsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*HumanitecProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			HUM_PROVIDER_ERR,
+			fmt.Sprintf("Expected *HumanitecProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.dpClient = providerData.DpClient
+	d.orgId = providerData.OrgId
+}
+
+func (d *DeploymentLogsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DeploymentLogsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deploymentUuid, err := uuid.Parse(data.DeploymentId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deployment_id"), HUM_INPUT_ERR, fmt.Sprintf("The deployment ID must be a UUID, got error: %s", err))
+		return
+	}
+	identity, err := age.ParseX25519Identity(data.Identity.ValueString())
+	if err != nil {
+		// The error of the age library does not include the identity, so it is safe to show.
+		resp.Diagnostics.AddAttributeError(path.Root("identity"), HUM_INPUT_ERR, fmt.Sprintf("The identity must be an age identity, got error: %s", err))
+		return
+	}
+
+	logs, err := fetchEncryptedDeploymentLogs(ctx, d.dpClient, d.orgId, deploymentUuid, identity)
+	var noIdentityMatch *age.NoIdentityMatchError
+	if errors.As(err, &noIdentityMatch) {
+		resp.Diagnostics.AddAttributeError(path.Root("identity"), HUM_INPUT_ERR, fmt.Sprintf("The logs of deployment %s were not encrypted for this identity.", deploymentUuid))
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to fetch the deployment logs: %s", err))
+		return
+	}
+
+	lines := make([]DeploymentLogLineModel, 0)
+	for _, line := range parseDeploymentLogLines(logs) {
+		if !data.Level.IsNull() && !strings.EqualFold(line.Level, data.Level.ValueString()) {
+			continue
+		} else if !data.Contains.IsNull() && !strings.Contains(line.Message, data.Contains.ValueString()) {
+			continue
+		}
+		lines = append(lines, DeploymentLogLineModel{
+			Timestamp: toStringValueOrNil(ref.RefStringEmptyNil(line.Timestamp)),
+			Level:     toStringValueOrNil(ref.RefStringEmptyNil(line.Level)),
+			Message:   types.StringValue(line.Message),
+		})
+	}
+
+	linesValue, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: deploymentLogLineAttributeTypes()}, lines)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Lines = linesValue
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// deploymentLogLine is a parsed line of the runner logs. Fields that are not present in the line are empty.
+type deploymentLogLine struct {
+	Timestamp string
+	Level     string
+	Message   string
+}
+
+var deploymentLogLevels = map[string]string{
+	"trace":   "trace",
+	"debug":   "debug",
+	"info":    "info",
+	"warn":    "warn",
+	"warning": "warn",
+	"error":   "error",
+	"fatal":   "fatal",
+}
+
+// parseDeploymentLogLines splits the logs into lines and parses each line. Empty lines are skipped.
+func parseDeploymentLogLines(logs string) []deploymentLogLine {
+	var lines []deploymentLogLine
+	for _, raw := range strings.Split(logs, "\n") {
+		if raw = strings.TrimRight(raw, "\r"); strings.TrimSpace(raw) != "" {
+			lines = append(lines, parseDeploymentLogLine(raw))
+		}
+	}
+	return lines
+}
+
+// parseDeploymentLogLine parses a JSON encoded log line, or a text line optionally starting with an RFC3339
+// timestamp and a log level. Lines in other formats are returned as the message.
+func parseDeploymentLogLine(raw string) deploymentLogLine {
+	var structured map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &structured); err == nil {
+		line := deploymentLogLine{Message: raw}
+		for _, key := range []string{"time", "timestamp", "ts"} {
+			if v, ok := structured[key].(string); ok {
+				line.Timestamp = normalizeLogTimestamp(v)
+				break
+			}
+		}
+		for _, key := range []string{"level", "lvl"} {
+			if v, ok := structured[key].(string); ok {
+				line.Level = strings.ToLower(v)
+				if level, ok := deploymentLogLevels[line.Level]; ok {
+					line.Level = level
+				}
+				break
+			}
+		}
+		for _, key := range []string{"msg", "message"} {
+			if v, ok := structured[key].(string); ok {
+				line.Message = v
+				break
+			}
+		}
+		return line
+	}
+
+	line := deploymentLogLine{Message: raw}
+	rest := raw
+	if field, remainder, found := strings.Cut(rest, " "); found {
+		if timestamp := normalizeLogTimestamp(field); timestamp != "" {
+			line.Timestamp = timestamp
+			rest = remainder
+		}
+	}
+	if field, remainder, found := strings.Cut(rest, " "); found {
+		field = strings.TrimPrefix(strings.Trim(field, "[]:"), "level=")
+		if level, ok := deploymentLogLevels[strings.ToLower(field)]; ok {
+			line.Level = level
+			rest = remainder
+		}
+	}
+	if line.Timestamp != "" || line.Level != "" {
+		line.Message = strings.TrimSpace(rest)
+	}
+	return line
+}
+
+// normalizeLogTimestamp returns the timestamp in RFC3339 format, or an empty string if it is not an RFC3339 timestamp.
+func normalizeLogTimestamp(value string) string {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccDeploymentLogsDataSource_invalid_identity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "platform-orchestrator_deployment_logs" "logs" {
+  deployment_id = "00000000-0000-0000-0000-000000000000"
+  identity      = "not-an-identity"
+}
+`, ExpectError: regexp.MustCompile(`The identity must be an age identity`),
+			},
+		},
+	})
+}
+
+func TestParseDeploymentLogLines(t *testing.T) {
+	logs := "2025-01-02T03:04:05.123Z INFO starting runner\n" +
+		"\n" +
+		"2025-01-02T03:04:06Z [warning] retrying\r\n" +
+		`{"time":"2025-01-02T03:04:07+01:00","level":"ERROR","msg":"apply failed"}` + "\n" +
+		"plain output\n"
+
+	assert.Equal(t, []deploymentLogLine{
+		{Timestamp: "2025-01-02T03:04:05Z", Level: "info", Message: "starting runner"},
+		{Timestamp: "2025-01-02T03:04:06Z", Level: "warn", Message: "retrying"},
+		{Timestamp: "2025-01-02T03:04:07+01:00", Level: "error", Message: "apply failed"},
+		{Message: "plain output"},
+	}, parseDeploymentLogLines(logs))
+}
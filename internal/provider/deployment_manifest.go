@@ -0,0 +1,298 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+	"terraform-provider-humanitec-v2/internal/ref"
+)
+
+// DeploymentWorkloadModel is the structured representation of a workload in a deployment manifest.
+type DeploymentWorkloadModel struct {
+	Resources types.Map `tfsdk:"resources"`
+	Variables types.Map `tfsdk:"variables"`
+	Outputs   types.Map `tfsdk:"outputs"`
+}
+
+// DeploymentManifestResourceModel is the structured representation of a resource in a deployment manifest.
+type DeploymentManifestResourceModel struct {
+	Type   types.String         `tfsdk:"type"`
+	Class  types.String         `tfsdk:"class"`
+	Id     types.String         `tfsdk:"id"`
+	Params jsontypes.Normalized `tfsdk:"params"`
+}
+
+// deploymentManifestResourceAttributes returns the schema of a resource in a deployment manifest, shared between the
+// workload resources and the shared resources.
+func deploymentManifestResourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"type": schema.StringAttribute{
+			MarkdownDescription: "The resource type to provision.",
+			Required:            true,
+			Validators: []validator.String{
+				stringvalidator.LengthBetween(2, 63),
+				stringvalidator.RegexMatches(regexp.MustCompile("^[A-Za-z0-9][A-Za-z0-9-]{0,61}[A-Za-z0-9]$"), "must be a valid resource type"),
+			},
+		},
+		"class": schema.StringAttribute{
+			MarkdownDescription: "A resource class requested by the resource graph. 'default' is the default value.",
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.LengthBetween(1, 63),
+				stringvalidator.RegexMatches(regexp.MustCompile("^[A-Za-z0-9][A-Za-z0-9-]{0,61}[A-Za-z0-9]$"), "must be a valid identifier of alphanumerics and hyphens"),
+			},
+		},
+		"id": schema.StringAttribute{
+			MarkdownDescription: "A specific resource id requested by the resource graph.",
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.LengthBetween(1, 63),
+				stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9]+(?:-+[a-z0-9]+)*(?:\.[a-z0-9]+(?:-+[a-z0-9]+)*)*$`), "must be one or more dot-separated parts of lowercase alphanumerics and hyphens"),
+			},
+		},
+		"params": schema.StringAttribute{
+			MarkdownDescription: "A JSON encoded object of parameters to pass to the resource provisioning.",
+			Optional:            true,
+			CustomType:          jsontypes.NormalizedType{},
+		},
+	}
+}
+
+// deploymentWorkloadsAttribute returns the schema of the structured workloads of a deployment manifest.
+func deploymentWorkloadsAttribute() schema.MapNestedAttribute {
+	return schema.MapNestedAttribute{
+		MarkdownDescription: "The workloads to deploy, keyed by workload name. This is a structured alternative to `manifest` that is validated at plan time. Exactly one of `manifest`, `workloads`, or `rollback_to_deployment_id` must be set.",
+		Optional:            true,
+		Validators: []validator.Map{
+			mapvalidator.SizeAtMost(100),
+		},
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"resources": schema.MapNestedAttribute{
+					MarkdownDescription: "The resources of the workload, keyed by resource name.",
+					Optional:            true,
+					Validators: []validator.Map{
+						mapvalidator.SizeAtMost(100),
+					},
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: deploymentManifestResourceAttributes(),
+					},
+				},
+				"variables": schema.MapAttribute{
+					MarkdownDescription: "The variables of the workload. Deprecated by the Platform Orchestrator, use `outputs` instead.",
+					Optional:            true,
+					ElementType:         types.StringType,
+					Validators: []validator.Map{
+						mapvalidator.SizeAtMost(100),
+					},
+				},
+				"outputs": schema.MapAttribute{
+					MarkdownDescription: "The outputs of the workload.",
+					Optional:            true,
+					ElementType:         types.StringType,
+					Validators: []validator.Map{
+						mapvalidator.SizeAtMost(100),
+					},
+				},
+			},
+		},
+	}
+}
+
+// deploymentSharedAttribute returns the schema of the structured shared resources of a deployment manifest.
+func deploymentSharedAttribute() schema.MapNestedAttribute {
+	return schema.MapNestedAttribute{
+		MarkdownDescription: "The shared resources to deploy, keyed by resource name. Requires `workloads` to be set, use an empty map if the deployment only has shared resources.",
+		Optional:            true,
+		Validators: []validator.Map{
+			mapvalidator.SizeAtMost(100),
+			mapvalidator.AlsoRequires(path.MatchRoot("workloads")),
+		},
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: deploymentManifestResourceAttributes(),
+		},
+	}
+}
+
+// isFullyKnown returns true if the value and all of its nested values are known.
+func isFullyKnown(ctx context.Context, value attr.Value) bool {
+	raw, err := value.ToTerraformValue(ctx)
+	return err == nil && raw.IsFullyKnown()
+}
+
+// toDeploymentManifestFromModel builds a deployment manifest from the structured workloads and shared resources.
+func toDeploymentManifestFromModel(ctx context.Context, workloads, shared basetypes.MapValue) (canyondp.DeploymentManifest, error) {
+	manifest := canyondp.DeploymentManifest{
+		Workloads: make(map[string]canyondp.DeploymentManifestWorkload),
+	}
+	if !workloads.IsNull() && !workloads.IsUnknown() {
+		for name, value := range workloads.Elements() {
+			workloadObj, ok := value.(basetypes.ObjectValue)
+			if !ok {
+				return manifest, fmt.Errorf("expected object value for workload %s", name)
+			}
+
+			var workloadModel DeploymentWorkloadModel
+			if diags := workloadObj.As(ctx, &workloadModel, basetypes.ObjectAsOptions{}); diags.HasError() {
+				return manifest, fmt.Errorf("failed to convert workload %s: %v", name, diags.Errors())
+			}
+
+			resources, err := toDeploymentManifestResourcesFromModel(ctx, workloadModel.Resources)
+			if err != nil {
+				return manifest, fmt.Errorf("workload %s: %w", name, err)
+			}
+			workload := canyondp.DeploymentManifestWorkload{Resources: resources}
+			if !workloadModel.Outputs.IsNull() {
+				if diags := workloadModel.Outputs.ElementsAs(ctx, &workload.Outputs, false); diags.HasError() {
+					return manifest, fmt.Errorf("failed to convert outputs of workload %s: %v", name, diags.Errors())
+				}
+			}
+			if !workloadModel.Variables.IsNull() {
+				//nolint:staticcheck // variables are still supported by the Platform Orchestrator.
+				if diags := workloadModel.Variables.ElementsAs(ctx, &workload.Variables, false); diags.HasError() {
+					return manifest, fmt.Errorf("failed to convert variables of workload %s: %v", name, diags.Errors())
+				}
+			}
+			manifest.Workloads[name] = workload
+		}
+	}
+
+	sharedResources, err := toDeploymentManifestResourcesFromModel(ctx, shared)
+	if err != nil {
+		return manifest, fmt.Errorf("shared: %w", err)
+	}
+	manifest.Shared = sharedResources
+	return manifest, nil
+}
+
+func toDeploymentManifestResourcesFromModel(ctx context.Context, resources basetypes.MapValue) (map[string]canyondp.DeploymentManifestResource, error) {
+	if resources.IsNull() || resources.IsUnknown() {
+		return nil, nil
+	}
+	result := make(map[string]canyondp.DeploymentManifestResource)
+	for name, value := range resources.Elements() {
+		resourceObj, ok := value.(basetypes.ObjectValue)
+		if !ok {
+			return nil, fmt.Errorf("expected object value for resource %s", name)
+		}
+
+		var resourceModel DeploymentManifestResourceModel
+		if diags := resourceObj.As(ctx, &resourceModel, basetypes.ObjectAsOptions{}); diags.HasError() {
+			return nil, fmt.Errorf("failed to convert resource %s: %v", name, diags.Errors())
+		}
+
+		var params map[string]interface{}
+		if !resourceModel.Params.IsNull() && !resourceModel.Params.IsUnknown() {
+			if err := json.Unmarshal([]byte(resourceModel.Params.ValueString()), &params); err != nil {
+				return nil, fmt.Errorf("failed to parse params of resource %s: %s", name, err)
+			}
+		}
+
+		result[name] = canyondp.DeploymentManifestResource{
+			Type:   resourceModel.Type.ValueString(),
+			Class:  ref.RefStringEmptyNil(resourceModel.Class.ValueString()),
+			Id:     ref.RefStringEmptyNil(resourceModel.Id.ValueString()),
+			Params: params,
+		}
+	}
+	return result, nil
+}
+
+func deploymentManifestResourceAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"type":   types.StringType,
+		"class":  types.StringType,
+		"id":     types.StringType,
+		"params": jsontypes.NormalizedType{},
+	}
+}
+
+func deploymentWorkloadAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"resources": types.MapType{ElemType: types.ObjectType{AttrTypes: deploymentManifestResourceAttributeTypes()}},
+		"variables": types.MapType{ElemType: types.StringType},
+		"outputs":   types.MapType{ElemType: types.StringType},
+	}
+}
+
+// toDeploymentWorkloadsValue converts the workloads of a deployment manifest into their structured representation.
+func toDeploymentWorkloadsValue(ctx context.Context, workloads map[string]canyondp.DeploymentManifestWorkload) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	elements := make(map[string]DeploymentWorkloadModel, len(workloads))
+	for name, workload := range workloads {
+		resources, dd := toDeploymentManifestResourcesValue(ctx, workload.Resources)
+		diags.Append(dd...)
+		model := DeploymentWorkloadModel{
+			Resources: resources,
+			Variables: types.MapNull(types.StringType),
+			Outputs:   types.MapNull(types.StringType),
+		}
+		if workload.Outputs != nil {
+			model.Outputs, dd = types.MapValueFrom(ctx, types.StringType, workload.Outputs)
+			diags.Append(dd...)
+		}
+		//nolint:staticcheck // variables are still supported by the Platform Orchestrator.
+		if workload.Variables != nil {
+			model.Variables, dd = types.MapValueFrom(ctx, types.StringType, workload.Variables)
+			diags.Append(dd...)
+		}
+		elements[name] = model
+	}
+	if diags.HasError() {
+		return types.MapNull(types.ObjectType{AttrTypes: deploymentWorkloadAttributeTypes()}), diags
+	}
+
+	value, dd := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: deploymentWorkloadAttributeTypes()}, elements)
+	diags.Append(dd...)
+	return value, diags
+}
+
+// toDeploymentManifestResourcesValue converts the resources of a deployment manifest into their structured
+// representation. Missing resources are returned as a null map.
+func toDeploymentManifestResourcesValue(ctx context.Context, resources map[string]canyondp.DeploymentManifestResource) (types.Map, diag.Diagnostics) {
+	elemType := types.ObjectType{AttrTypes: deploymentManifestResourceAttributeTypes()}
+	if resources == nil {
+		return types.MapNull(elemType), nil
+	}
+
+	var diags diag.Diagnostics
+	elements := make(map[string]DeploymentManifestResourceModel, len(resources))
+	for name, resource := range resources {
+		model := DeploymentManifestResourceModel{
+			Type:   types.StringValue(resource.Type),
+			Class:  types.StringPointerValue(resource.Class),
+			Id:     types.StringPointerValue(resource.Id),
+			Params: jsontypes.NewNormalizedNull(),
+		}
+		if resource.Params != nil {
+			raw, err := json.Marshal(resource.Params)
+			if err != nil {
+				diags.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Failed to serialize params of resource %s: %s", name, err))
+				continue
+			}
+			model.Params = jsontypes.NewNormalizedValue(string(raw))
+		}
+		elements[name] = model
+	}
+	if diags.HasError() {
+		return types.MapNull(elemType), diags
+	}
+
+	value, dd := types.MapValueFrom(ctx, elemType, elements)
+	diags.Append(dd...)
+	return value, diags
+}
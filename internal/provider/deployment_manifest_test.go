@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+	"terraform-provider-humanitec-v2/internal/ref"
+)
+
+func TestToDeploymentWorkloadsValue(t *testing.T) {
+	ctx := context.Background()
+	manifest := canyondp.DeploymentManifest{
+		Workloads: map[string]canyondp.DeploymentManifestWorkload{
+			"main": {
+				Resources: map[string]canyondp.DeploymentManifestResource{
+					"db": {Type: "postgres", Class: ref.Ref("large"), Params: map[string]interface{}{"version": "16"}},
+				},
+				Outputs: map[string]string{"db_host": "${resources.db.outputs.host}"},
+			},
+			"empty": {},
+		},
+		Shared: map[string]canyondp.DeploymentManifestResource{
+			"dns": {Type: "dns", Id: ref.Ref("shared.dns")},
+		},
+	}
+
+	workloads, diags := toDeploymentWorkloadsValue(ctx, manifest.Workloads)
+	require.False(t, diags.HasError(), diags)
+	shared, diags := toDeploymentManifestResourcesValue(ctx, manifest.Shared)
+	require.False(t, diags.HasError(), diags)
+
+	// The structured representation converts back into the same manifest.
+	actual, err := toDeploymentManifestFromModel(ctx, workloads, shared)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, actual)
+}
+
+func TestToDeploymentManifestResourcesValue_nil(t *testing.T) {
+	value, diags := toDeploymentManifestResourcesValue(context.Background(), nil)
+	require.False(t, diags.HasError(), diags)
+	assert.True(t, value.IsNull())
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"gopkg.in/yaml.v3"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+)
+
+var _ basetypes.StringTypable = (*DeploymentManifestType)(nil)
+var _ basetypes.StringValuable = (*DeploymentManifestValue)(nil)
+var _ basetypes.StringValuableWithSemanticEquals = (*DeploymentManifestValue)(nil)
+var _ xattr.ValidateableAttribute = (*DeploymentManifestValue)(nil)
+
+// DeploymentManifestType is a string type holding a YAML or JSON encoded deployment manifest. Values are
+// semantically equal when they describe the same manifest, regardless of formatting, key order, or encoding.
+type DeploymentManifestType struct {
+	basetypes.StringType
+}
+
+func (t DeploymentManifestType) String() string {
+	return "provider.DeploymentManifestType"
+}
+
+func (t DeploymentManifestType) ValueType(ctx context.Context) attr.Value {
+	return DeploymentManifestValue{}
+}
+
+func (t DeploymentManifestType) Equal(o attr.Type) bool {
+	other, ok := o.(DeploymentManifestType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t DeploymentManifestType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return DeploymentManifestValue{StringValue: in}, nil
+}
+
+func (t DeploymentManifestType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+	return stringValuable, nil
+}
+
+// DeploymentManifestValue is a value of DeploymentManifestType.
+type DeploymentManifestValue struct {
+	basetypes.StringValue
+}
+
+func NewDeploymentManifestNull() DeploymentManifestValue {
+	return DeploymentManifestValue{StringValue: basetypes.NewStringNull()}
+}
+
+func NewDeploymentManifestUnknown() DeploymentManifestValue {
+	return DeploymentManifestValue{StringValue: basetypes.NewStringUnknown()}
+}
+
+func NewDeploymentManifestValue(value string) DeploymentManifestValue {
+	return DeploymentManifestValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func (v DeploymentManifestValue) Type(_ context.Context) attr.Type {
+	return DeploymentManifestType{}
+}
+
+func (v DeploymentManifestValue) Equal(o attr.Value) bool {
+	other, ok := o.(DeploymentManifestValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals returns true if both values parse into the same deployment manifest.
+func (v DeploymentManifestValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(DeploymentManifestValue)
+	if !ok {
+		diags.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Expected a deployment manifest value, got: %T. Please report this issue to the provider developers.", newValuable))
+		return false, diags
+	}
+
+	oldNormalized, err := normalizeDeploymentManifest(v.ValueString())
+	if err != nil {
+		// The prior value can not be compared, so treat the new value as a change.
+		return false, diags
+	}
+	newNormalized, err := normalizeDeploymentManifest(newValue.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	return oldNormalized == newNormalized, diags
+}
+
+func (v DeploymentManifestValue) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsUnknown() || v.IsNull() {
+		return
+	}
+
+	if _, err := parseDeploymentManifest(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, HUM_INPUT_ERR, fmt.Sprintf("The manifest is not a valid YAML or JSON encoded deployment manifest: %s", err))
+	}
+}
+
+// ToDeploymentManifest parses the value into a deployment manifest.
+func (v DeploymentManifestValue) ToDeploymentManifest() (canyondp.DeploymentManifest, error) {
+	if v.IsNull() || v.IsUnknown() {
+		return canyondp.DeploymentManifest{}, fmt.Errorf("manifest is null or unknown")
+	}
+	return parseDeploymentManifest(v.ValueString())
+}
+
+// deploymentManifestsEqual returns true if both values are equal or describe the same deployment manifest.
+func deploymentManifestsEqual(ctx context.Context, a, b DeploymentManifestValue) bool {
+	if a.Equal(b) {
+		return true
+	}
+	if a.IsNull() || a.IsUnknown() || b.IsNull() || b.IsUnknown() {
+		return false
+	}
+	equal, diags := a.StringSemanticEquals(ctx, b)
+	return equal && !diags.HasError()
+}
+
+func parseDeploymentManifest(raw string) (canyondp.DeploymentManifest, error) {
+	var manifest canyondp.DeploymentManifest
+	if err := yaml.Unmarshal([]byte(raw), &manifest); err != nil {
+		return manifest, err
+	}
+	return manifest, nil
+}
+
+// normalizeDeploymentManifest returns a canonical JSON encoding of the manifest. Empty collections are omitted and
+// map keys are sorted, so equivalent manifests always have the same encoding.
+func normalizeDeploymentManifest(raw string) (string, error) {
+	manifest, err := parseDeploymentManifest(raw)
+	if err != nil {
+		return "", err
+	}
+	if manifest.Workloads == nil {
+		manifest.Workloads = make(map[string]canyondp.DeploymentManifestWorkload)
+	}
+	out, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeploymentManifestValue_StringSemanticEquals(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		a, b     string
+		expected bool
+	}{
+		{
+			name:     "identical",
+			a:        `{"workloads":{"main":{}}}`,
+			b:        `{"workloads":{"main":{}}}`,
+			expected: true,
+		},
+		{
+			name:     "yaml and json",
+			a:        `{"workloads":{"main":{"resources":{"db":{"type":"postgres","params":{"a":1,"b":"x"}}}}}}`,
+			b:        "workloads:\n  main:\n    resources:\n      db:\n        params:\n          b: x\n          a: 1\n        type: postgres\n",
+			expected: true,
+		},
+		{
+			name:     "empty collections",
+			a:        `{"workloads":{"main":{"outputs":{}}},"shared":{}}`,
+			b:        `{"workloads":{"main":{}}}`,
+			expected: true,
+		},
+		{
+			name:     "missing workloads",
+			a:        `{}`,
+			b:        `{"workloads":{}}`,
+			expected: true,
+		},
+		{
+			name:     "different params",
+			a:        `{"workloads":{"main":{"resources":{"db":{"type":"postgres","params":{"a":1}}}}}}`,
+			b:        `{"workloads":{"main":{"resources":{"db":{"type":"postgres","params":{"a":2}}}}}}`,
+			expected: false,
+		},
+		{
+			name:     "invalid",
+			a:        `{"workloads":{}}`,
+			b:        `{"workloads":`,
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			equal, diags := NewDeploymentManifestValue(tc.a).StringSemanticEquals(context.Background(), NewDeploymentManifestValue(tc.b))
+			assert.False(t, diags.HasError())
+			assert.Equal(t, tc.expected, equal)
+		})
+	}
+}
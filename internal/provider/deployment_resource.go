@@ -2,10 +2,9 @@ package provider
 
 import (
 	"context"
-	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"regexp"
 	"strings"
@@ -14,24 +13,48 @@ import (
 	"filippo.io/age"
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"gopkg.in/yaml.v3"
 
 	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
 	"terraform-provider-humanitec-v2/internal/ref"
 )
 
+// defaultDeploymentFailureLogLines is the default number of runner log lines reported when a deployment fails.
+const defaultDeploymentFailureLogLines = 25
+
+// The behaviors available when a deployment resource is destroyed.
+const (
+	deploymentOnDestroyNone     = "none"
+	deploymentOnDestroyRollback = "rollback"
+	deploymentOnDestroyEmpty    = "empty"
+)
+
+// The behaviors available when a newer deployment to the environment is found on refresh.
+const (
+	deploymentDriftPolicyIgnore   = "ignore"
+	deploymentDriftPolicyWarn     = "warn"
+	deploymentDriftPolicyRedeploy = "redeploy"
+)
+
 var _ resource.Resource = &DeploymentResource{}
 var _ resource.ResourceWithConfigure = &DeploymentResource{}
+var _ resource.ResourceWithModifyPlan = &DeploymentResource{}
+var _ resource.ResourceWithImportState = &DeploymentResource{}
 
 func NewDeploymentResource() resource.Resource {
 	return &DeploymentResource{}
@@ -40,22 +63,36 @@ func NewDeploymentResource() resource.Resource {
 type DeploymentResource struct {
 	dpClient canyondp.ClientWithResponsesInterface
 	orgId    string
+	locks    *DeploymentLocks
 }
 
 type DeploymentResourceModel struct {
-	ProjectId     types.String   `tfsdk:"project_id"`
-	EnvId         types.String   `tfsdk:"env_id"`
-	Manifest      types.String   `tfsdk:"manifest"`
-	Mode          types.String   `tfsdk:"mode"`
-	Id            types.String   `tfsdk:"id"`
-	CreatedAt     types.String   `tfsdk:"created_at"`
-	CompletedAt   types.String   `tfsdk:"completed_at"`
-	Status        types.String   `tfsdk:"status"`
-	StatusMessage types.String   `tfsdk:"status_message"`
-	RunnerId      types.String   `tfsdk:"runner_id"`
-	WaitFor       types.Bool     `tfsdk:"wait_for"`
-	Outputs       types.String   `tfsdk:"outputs"`
-	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+	ProjectId              types.String            `tfsdk:"project_id"`
+	EnvId                  types.String            `tfsdk:"env_id"`
+	Manifest               DeploymentManifestValue `tfsdk:"manifest"`
+	Workloads              types.Map               `tfsdk:"workloads"`
+	Shared                 types.Map               `tfsdk:"shared"`
+	RollbackToDeploymentId types.String            `tfsdk:"rollback_to_deployment_id"`
+	Mode                   types.String            `tfsdk:"mode"`
+	RunnerLogLevel         types.String            `tfsdk:"runner_log_level"`
+	Id                     types.String            `tfsdk:"id"`
+	CreatedAt              types.String            `tfsdk:"created_at"`
+	CompletedAt            types.String            `tfsdk:"completed_at"`
+	Status                 types.String            `tfsdk:"status"`
+	StatusMessage          types.String            `tfsdk:"status_message"`
+	RunnerId               types.String            `tfsdk:"runner_id"`
+	WaitFor                types.Bool              `tfsdk:"wait_for"`
+	WaitOnRefresh          types.Bool              `tfsdk:"wait_on_refresh"`
+	RollbackOnFailure      types.Bool              `tfsdk:"rollback_on_failure"`
+	FailureLogLines        types.Int64             `tfsdk:"failure_log_lines"`
+	OnDestroy              types.String            `tfsdk:"on_destroy"`
+	DriftPolicy            types.String            `tfsdk:"drift_policy"`
+	MaxRemovedResources    types.Int64             `tfsdk:"max_removed_resources"`
+	MaxRemovedNodes        types.Int64             `tfsdk:"max_removed_nodes"`
+	Outputs                types.String            `tfsdk:"outputs"`
+	Metrics                types.Object            `tfsdk:"metrics"`
+	PlannedChanges         types.List              `tfsdk:"planned_changes"`
+	Timeouts               timeouts.Value          `tfsdk:"timeouts"`
 }
 
 func (d *DeploymentResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
@@ -95,10 +132,28 @@ func (d *DeploymentResource) Schema(ctx context.Context, request resource.Schema
 				},
 			},
 			"manifest": schema.StringAttribute{
-				MarkdownDescription: "The YAML/JSON encoded manifest to deploy.",
-				Required:            true,
+				MarkdownDescription: "The YAML/JSON encoded manifest to deploy. Exactly one of `manifest`, `workloads`, or `rollback_to_deployment_id` must be set. When `workloads` is set, this contains the manifest built from `workloads` and `shared`. When rolling back, this contains the effective manifest of the rollback deployment. Manifests are compared semantically, so formatting, key order, or switching between YAML and JSON does not trigger a new deployment.",
+				Optional:            true,
+				Computed:            true,
+				CustomType:          DeploymentManifestType{},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("rollback_to_deployment_id"), path.MatchRoot("workloads")),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
+					rollbackManifestPlanModifier{},
+				},
+			},
+			"workloads": deploymentWorkloadsAttribute(),
+			"shared":    deploymentSharedAttribute(),
+			"rollback_to_deployment_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of a previous deployment in the same environment to roll back to. Exactly one of `manifest`, `workloads`, or `rollback_to_deployment_id` must be set.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+						"must be a valid deployment UUID.",
+					),
 				},
 			},
 			"mode": schema.StringAttribute{
@@ -110,6 +165,18 @@ func (d *DeploymentResource) Schema(ctx context.Context, request resource.Schema
 					stringvalidator.OneOf(string(canyondp.Deploy), string(canyondp.PlanOnly)),
 				},
 			},
+			"runner_log_level": schema.StringAttribute{
+				MarkdownDescription: "The log level of the runner executing the deployment (debug, info, warn, error). Changing only this attribute does not trigger a new deployment.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(canyondp.DeploymentCreateBodyRunnerLogLevelDebug),
+						string(canyondp.DeploymentCreateBodyRunnerLogLevelInfo),
+						string(canyondp.DeploymentCreateBodyRunnerLogLevelWarn),
+						string(canyondp.DeploymentCreateBodyRunnerLogLevelError),
+					),
+				},
+			},
 			"id": schema.StringAttribute{
 				MarkdownDescription: "The ID of the Deployment.",
 				Computed:            true,
@@ -135,19 +202,126 @@ func (d *DeploymentResource) Schema(ctx context.Context, request resource.Schema
 				Computed:            true,
 			},
 			"wait_for": schema.BoolAttribute{
-				MarkdownDescription: "Whether to wait for the deployment to complete. Defaults to true. If false, the output will be empty.",
+				MarkdownDescription: "Whether to wait for the deployment to complete. Defaults to true. If false, the outputs are filled in by a later refresh once the deployment has succeeded. Deployments to the same environment within one apply are queued: a deployment waits until the previous one has completed, or has only been created if the previous one sets `wait_for` to false, and the time spent waiting counts towards its `create` timeout.",
 				Computed:            true,
 				Optional:            true,
 				Default:             booldefault.StaticBool(true),
 			},
+			"wait_on_refresh": schema.BoolAttribute{
+				MarkdownDescription: "Whether a refresh waits for a deployment that has not completed yet, for example because `wait_for` is false or the apply was interrupted. Defaults to false. The refresh waits up to the `read` timeout.",
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"rollback_on_failure": schema.BoolAttribute{
+				MarkdownDescription: "Whether to roll the environment back to the last successful deployment when the deployment fails, within what is left of its timeout. Defaults to false and only applies when `wait_for` is true.",
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"failure_log_lines": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The number of trailing runner log lines to include in the error when the deployment fails. Defaults to %d. Set to 0 to not capture the runner logs.", defaultDeploymentFailureLogLines),
+				Computed:            true,
+				Optional:            true,
+				Default:             int64default.StaticInt64(defaultDeploymentFailureLogLines),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"on_destroy": schema.StringAttribute{
+				MarkdownDescription: "What to deploy to the environment when this resource is destroyed: 'none' (the default) leaves it as it is, 'rollback' redeploys the last successful deployment before this one, and 'empty' tears down its resources. Nothing is deployed for 'plan_only' deployments or after a failed create, and `max_removed_nodes` and `max_removed_resources` are not checked.",
+				Computed:            true,
+				Optional:            true,
+				Default:             stringdefault.StaticString(deploymentOnDestroyNone),
+				Validators: []validator.String{
+					stringvalidator.OneOf(deploymentOnDestroyNone, deploymentOnDestroyRollback, deploymentOnDestroyEmpty),
+				},
+			},
+			"drift_policy": schema.StringAttribute{
+				MarkdownDescription: "What to do when a refresh finds a newer deployment to the environment: 'ignore' (the default), 'warn', or 'redeploy' the configured manifest on the next apply. Only use 'warn' or 'redeploy' when this is the only deployment resource targeting the environment.",
+				Computed:            true,
+				Optional:            true,
+				Default:             stringdefault.StaticString(deploymentDriftPolicyIgnore),
+				Validators: []validator.String{
+					stringvalidator.OneOf(deploymentDriftPolicyIgnore, deploymentDriftPolicyWarn, deploymentDriftPolicyRedeploy),
+				},
+			},
+			"max_removed_resources": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of Terraform resources the deployment may remove, checked with a plan only deployment that counts towards the `create` timeout.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"max_removed_nodes": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of resource graph nodes the deployment may remove, checked with a dry-run deployment.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
 			"outputs": schema.StringAttribute{
-				MarkdownDescription: "The JSON encoded outputs of the deployment.",
+				MarkdownDescription: "The JSON encoded outputs of the deployment. The key the outputs are encrypted with is kept in the private state of the resource, so the outputs can be read again on refresh. Outputs are not available for imported deployments.",
 				Computed:            true,
 				Sensitive:           true,
 			},
+			"metrics": schema.SingleNestedAttribute{
+				MarkdownDescription: "The metrics of the deployment. The Terraform resource counts are only known once the deployment has completed.",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"num_workloads": schema.Int64Attribute{
+						MarkdownDescription: "The number of workloads in the deployment.",
+						Computed:            true,
+					},
+					"num_resource_nodes": schema.Int64Attribute{
+						MarkdownDescription: "The number of resource nodes in the resource graph.",
+						Computed:            true,
+					},
+					"num_tf_resources": schema.Int64Attribute{
+						MarkdownDescription: "The number of Terraform resources.",
+						Computed:            true,
+					},
+					"num_tf_resources_added": schema.Int64Attribute{
+						MarkdownDescription: "The number of Terraform resources added.",
+						Computed:            true,
+					},
+					"num_tf_resources_changed": schema.Int64Attribute{
+						MarkdownDescription: "The number of Terraform resources changed.",
+						Computed:            true,
+					},
+					"num_tf_resources_removed": schema.Int64Attribute{
+						MarkdownDescription: "The number of Terraform resources removed.",
+						Computed:            true,
+					},
+				},
+			},
+			"planned_changes": schema.ListNestedAttribute{
+				MarkdownDescription: "The changes to the resource graph of the environment made by the deployment, known once the deployment has been created. The plan shows a preview of the changes as a warning, since the environment may change before the plan is applied.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The deterministic hash of the resource node.",
+							Computed:            true,
+						},
+						"resource": schema.StringAttribute{
+							MarkdownDescription: "The resource identifier of the node including the type, class, and id.",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The type of change (added, removed, params_changed, module_changed).",
+							Computed:            true,
+						},
+						"summary": schema.StringAttribute{
+							MarkdownDescription: "A human readable summary of the change.",
+							Computed:            true,
+						},
+					},
+				},
+			},
 		},
 		Blocks: map[string]schema.Block{
-			"timeouts": timeouts.Block(ctx, timeouts.Opts{Delete: true}),
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Delete: true}),
 		},
 	}
 }
@@ -168,104 +342,519 @@ func (d *DeploymentResource) Configure(ctx context.Context, request resource.Con
 
 	d.dpClient = providerData.DpClient
 	d.orgId = providerData.OrgId
+	d.locks = providerData.DeploymentLocks
 }
 
-func (d *DeploymentResource) doDeployment(ctx context.Context, data *DeploymentResourceModel, diags *diag.Diagnostics) (outputsKey *age.X25519Identity) {
+// lockEnvironment waits until no other deployment of this provider to the same environment is in progress. It gives
+// up with an error when the context is done first. The returned function must be called to release the lock.
+func (d *DeploymentResource) lockEnvironment(ctx context.Context, data DeploymentResourceModel, diags *diag.Diagnostics) (unlock func()) {
+	if d.locks == nil {
+		return func() {}
+	}
+
+	unlock, err := d.locks.Lock(ctx, data.ProjectId.ValueString(), data.EnvId.ValueString())
+	if err != nil {
+		diags.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Timed out waiting for another deployment to environment %s in project %s to complete, got error: %s", data.EnvId.ValueString(), data.ProjectId.ValueString(), err))
+		return nil
+	}
+	return unlock
+}
+
+// doDeployment creates the deployment described by the model. The nonce is part of the idempotency key of the request
+// and tells apart deployments of the same manifest: it is the ID of the deployment currently in the state when updating
+// or destroying, and empty when creating. A retried or resumed apply therefore picks up the deployment created by the
+// earlier attempt, and returns a nil outputs key since its outputs can not be decrypted anymore. When that deployment
+// has failed, or the API rejects the key because it was already used, the model is deployed again with a random key.
+func (d *DeploymentResource) doDeployment(ctx context.Context, data *DeploymentResourceModel, nonce string, diags *diag.Diagnostics) (outputsKey *age.X25519Identity) {
 	if data.Mode.IsNull() {
 		data.Mode = types.StringValue(string(canyondp.Deploy))
 	}
 	if data.WaitFor.IsNull() {
 		data.WaitFor = types.BoolValue(true)
 	}
+	if data.FailureLogLines.IsNull() {
+		data.FailureLogLines = types.Int64Value(defaultDeploymentFailureLogLines)
+	}
+	if data.OnDestroy.IsNull() {
+		data.OnDestroy = types.StringValue(deploymentOnDestroyNone)
+	}
+	if data.WaitOnRefresh.IsNull() {
+		data.WaitOnRefresh = types.BoolValue(false)
+	}
+	if data.RollbackOnFailure.IsNull() {
+		data.RollbackOnFailure = types.BoolValue(false)
+	}
+	if data.DriftPolicy.IsNull() {
+		data.DriftPolicy = types.StringValue(deploymentDriftPolicyIgnore)
+	}
 
-	var manifest canyondp.DeploymentManifest
-	if err := yaml.Unmarshal([]byte(data.Manifest.ValueString()), &manifest); err != nil {
-		diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to parse manifest, got error: %s", err))
+	body, err := toDeploymentCreateBody(ctx, *data)
+	if err != nil {
+		diags.AddError(HUM_INPUT_ERR, fmt.Sprintf("Unable to build deployment request: %s", err))
+		return
+	}
+	isRollback := body.RollbackToDeploymentId != nil
+	if !isRollback && data.Manifest.IsUnknown() {
+		// The structured manifest was not fully known at plan time.
+		if manifest, err := manifestToYaml(*body.Manifest); err != nil {
+			diags.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to serialize deployment manifest, got error: %s", err))
+			return
+		} else {
+			data.Manifest = NewDeploymentManifestValue(manifest)
+		}
+	}
+
+	// The idempotency key is derived from the request, so that a retried request or a resumed apply picks up the
+	// deployment created by an earlier attempt instead of creating another one.
+	idempotencyKey, err := deploymentIdempotencyKey(d.orgId, nonce, body)
+	if err != nil {
+		diags.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to build deployment idempotency key, got error: %s", err))
 		return
 	}
 
+	// The API only returns an existing deployment for the key if it is the last one to the environment, so remember
+	// the last deployment to tell it apart from a deployment created by this request.
+	last, err := fetchLastDeployment(ctx, d.dpClient, d.orgId, body.ProjectId, body.EnvId)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to read the last deployment to the environment", map[string]interface{}{"error": err.Error()})
+	}
+
+	// The same key is used to encrypt the outputs and, if requested, the runner logs of the deployment. Both are
+	// decrypted by the provider, the key is never sent to the API.
 	outputsKey, _ = age.GenerateX25519Identity()
-	if r, err := d.dpClient.CreateDeploymentWithResponse(
-		ctx, d.orgId, &canyondp.CreateDeploymentParams{IdempotencyKey: ref.Ref(uuid.NewString())},
-		canyondp.DeploymentCreateBody{
-			ProjectId:                 data.ProjectId.ValueString(),
-			EnvId:                     data.EnvId.ValueString(),
-			Manifest:                  &manifest,
-			Mode:                      canyondp.DeploymentCreateBodyMode(data.Mode.ValueString()),
-			EncryptedOutputsRecipient: ref.Ref(outputsKey.Recipient().String()),
-		},
-	); err != nil {
-		diags.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to create deployment, got error: %s", err))
+	request := body
+	request.EncryptedOutputsRecipient = ref.Ref(outputsKey.Recipient().String())
+	if data.FailureLogLines.ValueInt64() > 0 {
+		request.EncryptedLogsRecipient = ref.Ref(outputsKey.Recipient().String())
+	}
+	deployment, err := d.createDeployment(ctx, request, idempotencyKey)
+	if errors.Is(err, errIdempotencyKeyConflict) {
+		// The key was used within the last 24 hours for a deployment that is no longer the last one to the environment,
+		// for example because it was rolled back or torn down since.
+		tflog.Info(ctx, "Idempotency key already used by an earlier deployment, deploying again", map[string]interface{}{"idempotency_key": idempotencyKey})
+		deployment, err = d.createDeployment(ctx, request, uuid.NewString())
+	} else if err == nil && last != nil && deployment.Id == last.Id {
+		if deployment.CompletedAt != nil && deployment.Status != "succeeded" {
+			// Picking up a failed deployment, for example the one of the tainted instance this resource replaces, would
+			// never deploy the manifest again.
+			tflog.Info(ctx, "Deployment with the same idempotency key has failed, deploying again", map[string]interface{}{"deployment_id": deployment.Id.String()})
+			deployment, err = d.createDeployment(ctx, request, uuid.NewString())
+		} else {
+			diags.AddWarning(
+				"Existing deployment picked up",
+				fmt.Sprintf("Deployment %s was created by an earlier attempt to apply the same manifest and is picked up instead of deploying again. Its outputs were encrypted for that attempt and can not be read.", deployment.Id),
+			)
+			outputsKey = nil
+		}
+	}
+	if err != nil {
+		diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to create deployment, %s", err))
 		return
-	} else if r.StatusCode() != http.StatusCreated {
-		diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to create deployment, unexpected status code: %d, body: %s", r.StatusCode(), r.Body))
+	}
+
+	data.Id = types.StringValue(deployment.Id.String())
+	data.CreatedAt = types.StringValue(deployment.CreatedAt.Format(time.RFC3339))
+	data.CompletedAt = types.StringNull()
+	data.Outputs = types.StringNull()
+	data.Status = types.StringValue(deployment.Status)
+	data.StatusMessage = types.StringValue(deployment.StatusMessage)
+	data.RunnerId = types.StringValue(deployment.RunnerId)
+	metrics, dd := toDeploymentMetricsValue(ctx, deployment.Metrics)
+	diags.Append(dd...)
+	if diags.HasError() {
 		return
-	} else {
-		data.Id = types.StringValue(r.JSON201.Id.String())
-		data.CreatedAt = types.StringValue(r.JSON201.CreatedAt.Format(time.RFC3339))
-		data.CompletedAt = types.StringNull()
-		data.Outputs = types.StringNull()
-		data.Status = types.StringValue(r.JSON201.Status)
-		data.StatusMessage = types.StringValue(r.JSON201.StatusMessage)
-		data.RunnerId = types.StringValue(r.JSON201.RunnerId)
+	}
+	data.Metrics = metrics
+
+	if isRollback {
+		// The manifest of a rollback deployment is resolved by the Platform Orchestrator, so read it back to
+		// show what is actually being deployed.
+		if r, err := d.dpClient.GetDeploymentWithResponse(ctx, d.orgId, uuid.MustParse(data.Id.ValueString())); err != nil {
+			diags.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to read rollback deployment, got error: %s", err))
+			return
+		} else if r.StatusCode() != http.StatusOK {
+			diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to read rollback deployment, unexpected status code: %d, body: %s", r.StatusCode(), r.Body))
+			return
+		} else if manifest, err := manifestToYaml(r.JSON200.Manifest); err != nil {
+			diags.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to serialize rollback deployment manifest, got error: %s", err))
+			return
+		} else {
+			data.Manifest = NewDeploymentManifestValue(manifest)
+		}
 	}
 	return outputsKey
 }
 
-func (d *DeploymentResource) waitForDeployment(ctx context.Context, data *DeploymentResourceModel, diags *diag.Diagnostics, outputsKey *age.X25519Identity) {
-	deleteTimeout, dd := data.Timeouts.Create(ctx, DefaultAsyncTimeout)
-	if dd.HasError() {
-		diags.Append(dd...)
+// errIdempotencyKeyConflict is returned by createDeployment when the API rejects an idempotency key that was already
+// used.
+var errIdempotencyKeyConflict = errors.New("idempotency key already used")
+
+// createDeployment sends the create request of a deployment with the given idempotency key.
+func (d *DeploymentResource) createDeployment(ctx context.Context, body canyondp.DeploymentCreateBody, idempotencyKey string) (*canyondp.Deployment, error) {
+	if r, err := d.dpClient.CreateDeploymentWithResponse(ctx, d.orgId, &canyondp.CreateDeploymentParams{IdempotencyKey: &idempotencyKey}, body); err != nil {
+		return nil, fmt.Errorf("got error: %w", err)
+	} else if r.StatusCode() == http.StatusConflict && r.JSON409 != nil && strings.Contains(strings.ToLower(r.JSON409.Message), "idempotency") {
+		// Other conflicts, for example a missing project or environment, are reported as unexpected status codes.
+		return nil, fmt.Errorf("%w, body: %s", errIdempotencyKeyConflict, r.Body)
+	} else if r.StatusCode() != http.StatusCreated {
+		return nil, fmt.Errorf("unexpected status code: %d, body: %s", r.StatusCode(), r.Body)
+	} else {
+		return r.JSON201, nil
+	}
+}
+
+// dryRunDeployment validates the deployment described by the model without executing it and returns the diff of the
+// resource graph against the current state of the environment.
+func (d *DeploymentResource) dryRunDeployment(ctx context.Context, data DeploymentResourceModel) (*canyondp.DeploymentDiff, error) {
+	body, err := toDeploymentCreateBody(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	body.IsDryRun = true
+
+	if r, err := d.dpClient.CreateDeploymentWithResponse(ctx, d.orgId, &canyondp.CreateDeploymentParams{}, body); err != nil {
+		return nil, fmt.Errorf("unable to create dry-run deployment, got error: %w", err)
+	} else if r.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unable to create dry-run deployment, unexpected status code: %d, body: %s", r.StatusCode(), r.Body)
+	} else {
+		return &r.JSON200.Diff, nil
+	}
+}
+
+// readDeploymentChanges sets the planned changes of the model to the diff of its deployment against the previous
+// deployment to the environment. Failing to read the diff does not fail the deployment and only results in a warning.
+func (d *DeploymentResource) readDeploymentChanges(ctx context.Context, data *DeploymentResourceModel, diags *diag.Diagnostics) {
+	data.PlannedChanges = types.ListNull(types.ObjectType{AttrTypes: deploymentDiffChangeAttributeTypes()})
+
+	r, err := d.dpClient.CalculateDeploymentDiffWithResponse(ctx, d.orgId, uuid.MustParse(data.Id.ValueString()), &canyondp.CalculateDeploymentDiffParams{})
+	if err != nil {
+		diags.AddWarning(HUM_CLIENT_ERR, fmt.Sprintf("Unable to read the changes of deployment %s, got error: %s", data.Id.ValueString(), err))
+		return
+	} else if r.StatusCode() != http.StatusOK {
+		diags.AddWarning(HUM_API_ERR, fmt.Sprintf("Unable to read the changes of deployment %s, unexpected status code: %d, body: %s", data.Id.ValueString(), r.StatusCode(), r.Body))
+		return
+	}
+
+	changes, dd := toDeploymentDiffChangesValue(ctx, r.JSON200.Changes)
+	diags.Append(dd...)
+	if !dd.HasError() {
+		data.PlannedChanges = changes
+	}
+}
+
+// checkRemovalLimits aborts the deployment described by the model if it would remove more resource nodes or Terraform
+// resources than allowed by max_removed_nodes and max_removed_resources.
+func (d *DeploymentResource) checkRemovalLimits(ctx context.Context, data DeploymentResourceModel, diags *diag.Diagnostics) {
+	if (data.MaxRemovedNodes.IsNull() && data.MaxRemovedResources.IsNull()) || data.Mode.ValueString() == string(canyondp.PlanOnly) {
+		return
+	}
+
+	diff, err := d.dryRunDeployment(ctx, data)
+	if err != nil {
+		diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to check the resources removed by the deployment: %s", err))
+		return
+	}
+	removed := make([]string, 0)
+	for _, change := range diff.Changes {
+		if change.Type == canyondp.DeploymentDiffChangeTypeRemoved {
+			removed = append(removed, fmt.Sprintf("  - %s: %s", change.Resource, change.Summary))
+		}
+	}
+
+	if !data.MaxRemovedNodes.IsNull() && int64(len(removed)) > data.MaxRemovedNodes.ValueInt64() {
+		diags.AddError(
+			"Too many resource nodes removed",
+			fmt.Sprintf(
+				"Deploying to environment %s in project %s would remove %d resource nodes, more than max_removed_nodes (%d):\n%s",
+				data.EnvId.ValueString(), data.ProjectId.ValueString(), len(removed), data.MaxRemovedNodes.ValueInt64(), strings.Join(removed, "\n"),
+			),
+		)
+		return
+	}
+
+	if data.MaxRemovedResources.IsNull() {
+		return
+	}
+
+	body, err := toDeploymentCreateBody(ctx, data)
+	if err != nil {
+		diags.AddError(HUM_INPUT_ERR, fmt.Sprintf("Unable to build deployment request: %s", err))
+		return
+	}
+	body.PlanOnly = ref.Ref(true)
+
+	var planDeploymentId uuid.UUID
+	if r, err := d.dpClient.CreateDeploymentWithResponse(ctx, d.orgId, &canyondp.CreateDeploymentParams{IdempotencyKey: ref.Ref(uuid.NewString())}, body); err != nil {
+		diags.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to create plan only deployment, got error: %s", err))
+		return
+	} else if r.StatusCode() != http.StatusCreated {
+		diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to create plan only deployment, unexpected status code: %d, body: %s", r.StatusCode(), r.Body))
+		return
+	} else {
+		planDeploymentId = r.JSON201.Id
+	}
+
+	tflog.Info(ctx, "Waiting for plan only deployment to complete...", map[string]interface{}{"deployment_id": planDeploymentId.String()})
+	deployment, err := d.waitForDeploymentComplete(ctx, planDeploymentId)
+	if err != nil {
+		diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to wait for plan only deployment %s to complete, %s", planDeploymentId, err))
+		return
+	} else if deployment.Status != "succeeded" {
+		diags.AddError(HUM_API_ERR, fmt.Sprintf("Plan only deployment %s failed, unable to check the Terraform resources removed by the deployment: %s", planDeploymentId, deployment.StatusMessage))
+		return
+	} else if deployment.Metrics.NumTfResourcesRemoved == nil {
+		diags.AddError(HUM_API_ERR, fmt.Sprintf("Plan only deployment %s did not report the number of removed Terraform resources.", planDeploymentId))
 		return
 	}
-	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
-	defer cancel()
 
+	if numRemoved := int64(*deployment.Metrics.NumTfResourcesRemoved); numRemoved > data.MaxRemovedResources.ValueInt64() {
+		message := fmt.Sprintf(
+			"Deploying to environment %s in project %s would remove %d Terraform resources, more than max_removed_resources (%d), according to plan only deployment %s.",
+			data.EnvId.ValueString(), data.ProjectId.ValueString(), numRemoved, data.MaxRemovedResources.ValueInt64(), planDeploymentId,
+		)
+		if len(removed) > 0 {
+			message += fmt.Sprintf(" Removed resource nodes:\n%s", strings.Join(removed, "\n"))
+		}
+		diags.AddError("Too many Terraform resources removed", message)
+	}
+}
+
+// waitForDeployment waits for the deployment of the model to complete and reads its outputs, or reports its failure and
+// rolls it back if requested. It returns the outputs key of the deployment the model records in the end, which is the
+// key of the rollback deployment after a rollback.
+func (d *DeploymentResource) waitForDeployment(ctx context.Context, data *DeploymentResourceModel, diags *diag.Diagnostics, outputsKey *age.X25519Identity) (recordedKey *age.X25519Identity) {
+	recordedKey = outputsKey
 	deploymentUuid, err := uuid.Parse(data.Id.ValueString())
 	if err != nil {
 		diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to parse deployment ID, got error: %s", err))
 		return
 	}
 
+	deployment, err := d.waitForDeploymentComplete(ctx, deploymentUuid)
+	if err != nil {
+		diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to wait for deployment to complete, %s", err))
+		return
+	}
+
+	data.Status = types.StringValue(deployment.Status)
+	data.StatusMessage = types.StringValue(deployment.StatusMessage)
+	data.CompletedAt = types.StringValue(deployment.CompletedAt.Format(time.RFC3339))
+	metrics, dd := toDeploymentMetricsValue(ctx, deployment.Metrics)
+	diags.Append(dd...)
+	if diags.HasError() {
+		return
+	}
+	data.Metrics = metrics
+	if data.Status.ValueString() == "succeeded" {
+		// A deployment picked up from an earlier attempt has no outputs key, doDeployment already warned about it.
+		if outputsKey == nil {
+			return
+		}
+		var noIdentityMatch *age.NoIdentityMatchError
+		if outputs, err := fetchDeploymentOutputs(ctx, d.dpClient, d.orgId, deploymentUuid, outputsKey); errors.As(err, &noIdentityMatch) {
+			// The deployment was created by an earlier attempt of this apply, which held the only key for its outputs.
+			diags.AddWarning(HUM_API_ERR, fmt.Sprintf("The outputs of deployment %s were encrypted for an earlier attempt to create it and can not be read.", data.Id.ValueString()))
+		} else if err != nil {
+			diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to fetch the deployment outputs: %s", err))
+		} else {
+			data.Outputs = types.StringValue(outputs)
+		}
+	} else {
+		message := fmt.Sprintf("Deployment failed: %s", data.StatusMessage)
+		if lines := int(data.FailureLogLines.ValueInt64()); lines > 0 && outputsKey != nil {
+			if logs, err := fetchEncryptedDeploymentLogs(ctx, d.dpClient, d.orgId, deploymentUuid, outputsKey); err != nil {
+				message += fmt.Sprintf("\n\nUnable to read the runner logs: %s", err)
+			} else if tail := tailLines(logs, lines); len(tail) > 0 {
+				message += fmt.Sprintf("\n\nLast %d lines of the runner logs:\n%s", len(tail), strings.Join(tail, "\n"))
+			}
+		}
+		if data.RollbackOnFailure.ValueBool() && data.Mode.ValueString() != string(canyondp.PlanOnly) {
+			rollback, rollbackKey, outcome := d.rollbackFailedDeployment(ctx, *data, diags)
+			message += "\n\n" + outcome
+			if rollback != nil {
+				// The environment runs the rolled back manifest now. Record the rollback deployment instead of the failed
+				// one, so that the next plan differs from the state and deploys the configured manifest again.
+				copyDeploymentResult(data, *rollback)
+				data.RollbackToDeploymentId = rollback.RollbackToDeploymentId
+				recordedKey = rollbackKey
+			}
+		}
+		diags.AddError(HUM_CLIENT_ERR, message)
+	}
+	return
+}
+
+// rollbackFailedDeployment redeploys the last successful deployment before the failed deployment of the model and
+// waits for it to complete, within the deadline of the context that was used for the failed deployment. It returns the
+// model and the outputs key of the rollback deployment if it succeeded, and a description of the outcome of the
+// rollback. The warnings of the rollback are added to the diagnostics.
+func (d *DeploymentResource) rollbackFailedDeployment(ctx context.Context, data DeploymentResourceModel, diags *diag.Diagnostics) (*DeploymentResourceModel, *age.X25519Identity, string) {
+	previous, err := d.findPreviousDeployment(ctx, data)
+	if err != nil {
+		return nil, nil, fmt.Sprintf("Unable to roll back, failed to find the previous deployment: %s", err)
+	} else if previous == nil {
+		return nil, nil, fmt.Sprintf("No successful deployment to environment %s in project %s found before deployment %s, nothing to roll back to.", data.EnvId.ValueString(), data.ProjectId.ValueString(), data.Id.ValueString())
+	}
+	tflog.Info(ctx, "Rolling back environment after failed deployment", map[string]interface{}{"deployment_id": data.Id.ValueString(), "rollback_to_deployment_id": previous.Id.String()})
+
+	rollback := DeploymentResourceModel{
+		ProjectId:              data.ProjectId,
+		EnvId:                  data.EnvId,
+		RollbackToDeploymentId: types.StringValue(previous.Id.String()),
+		Mode:                   types.StringValue(string(canyondp.Deploy)),
+		RunnerLogLevel:         data.RunnerLogLevel,
+		FailureLogLines:        data.FailureLogLines,
+		Timeouts:               data.Timeouts,
+	}
+	var rollbackDiags diag.Diagnostics
+	rollbackKey := d.doDeployment(ctx, &rollback, data.Id.ValueString(), &rollbackDiags)
+	if !rollbackDiags.HasError() {
+		d.readDeploymentChanges(ctx, &rollback, &rollbackDiags)
+		d.waitForDeployment(ctx, &rollback, &rollbackDiags, rollbackKey)
+	}
+	diags.Append(rollbackDiags.Warnings()...)
+	if rollbackDiags.HasError() {
+		var details []string
+		for _, e := range rollbackDiags.Errors() {
+			details = append(details, e.Detail())
+		}
+		return nil, nil, fmt.Sprintf("Rollback to deployment %s failed: %s", previous.Id, strings.Join(details, "\n"))
+	}
+	return &rollback, rollbackKey, fmt.Sprintf("Rolled back to deployment %s in deployment %s.", previous.Id, rollback.Id.ValueString())
+}
+
+// waitForDeploymentComplete blocks until the deployment reaches a terminal status or the context is done.
+func (d *DeploymentResource) waitForDeploymentComplete(ctx context.Context, deploymentUuid uuid.UUID) (*canyondp.Deployment, error) {
 	for {
 		if r, err := d.dpClient.WaitForDeploymentCompleteWithResponse(ctx, d.orgId, deploymentUuid, &canyondp.WaitForDeploymentCompleteParams{}); err != nil {
 			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
 				continue
 			}
-			diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to wait for deployment to complete, got error: %s", err))
-			return
+			return nil, fmt.Errorf("got error: %w", err)
 		} else if r.StatusCode() == http.StatusRequestTimeout {
 			if err := ctx.Err(); err != nil {
-				diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to wait for deployment to complete, got error: %s", err))
-				return
+				return nil, fmt.Errorf("got error: %w", err)
 			}
 			continue
 		} else if r.StatusCode() != http.StatusOK {
-			diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to wait for deployment to complete, unexpected status code: %d, body: %s", r.StatusCode(), r.Body))
-			return
+			return nil, fmt.Errorf("unexpected status code: %d, body: %s", r.StatusCode(), r.Body)
 		} else {
-			data.Status = types.StringValue(r.JSON200.Status)
-			data.StatusMessage = types.StringValue(r.JSON200.StatusMessage)
-			data.CompletedAt = types.StringValue(r.JSON200.CompletedAt.Format(time.RFC3339))
-			if data.Status.ValueString() == "succeeded" {
-				if r, err := d.dpClient.GetDeploymentEncryptedOutputsWithResponse(ctx, d.orgId, deploymentUuid); err != nil {
-					diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to read deployment outputs, got error: %s", err))
-				} else if r.StatusCode() != http.StatusOK {
-					diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to read deployment outputs, unexpected status code: %d, body: %s", r.StatusCode(), r.Body))
-				} else {
-					if decrypted, err := age.Decrypt(base64.NewDecoder(base64.StdEncoding, strings.NewReader(r.JSON200.Raw)), outputsKey); err != nil {
-						diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to decrypt deployment outputs, got error: %s", err))
-					} else if raw, err := io.ReadAll(decrypted); err != nil {
-						diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to read deployment outputs, got error: %s", err))
-					} else {
-						data.Outputs = types.StringValue(string(raw))
-					}
-				}
+			return r.JSON200, nil
+		}
+	}
+}
+
+func (d *DeploymentResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	// Nothing to preview when the resource is being destroyed.
+	if request.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan DeploymentResourceModel
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Show the manifest built from the structured workloads so that it can be compared against the prior state.
+	if !plan.Workloads.IsNull() {
+		plan.Manifest = NewDeploymentManifestUnknown()
+		if isFullyKnown(ctx, plan.Workloads) && isFullyKnown(ctx, plan.Shared) {
+			if manifest, err := toDeploymentManifestFromModel(ctx, plan.Workloads, plan.Shared); err != nil {
+				response.Diagnostics.AddError(HUM_INPUT_ERR, fmt.Sprintf("Unable to build deployment manifest: %s", err))
+				return
+			} else if raw, err := manifestToYaml(manifest); err != nil {
+				response.Diagnostics.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to serialize deployment manifest, got error: %s", err))
+				return
 			} else {
-				diags.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Deployment failed: %s", data.StatusMessage))
+				plan.Manifest = NewDeploymentManifestValue(raw)
 			}
+		}
+		response.Diagnostics.Append(response.Plan.Set(ctx, &plan)...)
+	}
+
+	if !request.State.Raw.IsNull() {
+		var state DeploymentResourceModel
+		response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+		if response.Diagnostics.HasError() {
 			return
 		}
+		if !deploymentChanged(ctx, plan, state) {
+			copyDeploymentResult(&plan, state)
+			response.Diagnostics.Append(response.Plan.Set(ctx, &plan)...)
+			return
+		}
+	}
+
+	// The provider may not be configured yet, or the deployment may depend on values only known after apply.
+	if d.dpClient == nil || plan.ProjectId.IsUnknown() || plan.EnvId.IsUnknown() || plan.Mode.IsUnknown() ||
+		plan.RollbackToDeploymentId.IsUnknown() || (plan.RollbackToDeploymentId.IsNull() && plan.Manifest.IsUnknown()) {
+		return
+	}
+
+	// The preview is only reported as a warning, planned_changes stays unknown until the deployment is created. Terraform
+	// plans the resource again right before applying it, and a dry-run at that time may return different changes.
+	diff, err := d.dryRunDeployment(ctx, plan)
+	if err != nil {
+		response.Diagnostics.AddWarning(HUM_API_ERR, fmt.Sprintf("Unable to preview the deployment changes: %s", err))
+		return
+	}
+
+	if len(diff.Changes) > 0 {
+		lines := make([]string, 0, len(diff.Changes))
+		for _, change := range diff.Changes {
+			lines = append(lines, fmt.Sprintf("  - %s %s: %s", change.Type, change.Resource, change.Summary))
+		}
+		response.Diagnostics.AddWarning(
+			"Planned deployment changes",
+			fmt.Sprintf(
+				"Deploying to environment %s in project %s changes the resource graph (%d added, %d changed, %d removed):\n%s",
+				plan.EnvId.ValueString(), plan.ProjectId.ValueString(), diff.NumAdded, diff.NumChanged, diff.NumRemoved, strings.Join(lines, "\n"),
+			),
+		)
+	}
+}
+
+// applyDeployment creates the deployment described by the planned model for both Create and Update and, unless
+// wait_for is false, waits for it to complete. The nonce is passed on to doDeployment. The state and the outputs key
+// are saved as soon as the deployment has been created, so that a failed wait does not lose track of it.
+func (d *DeploymentResource) applyDeployment(ctx context.Context, data *DeploymentResourceModel, nonce string, state *tfsdk.State, private privateStateWriter, diags *diag.Diagnostics) {
+	createTimeout, dd := data.Timeouts.Create(ctx, DefaultAsyncTimeout)
+	diags.Append(dd...)
+	if diags.HasError() {
+		return
+	}
+
+	// Waiting for the lock, the plan only deployment and the deployment all count towards the same timeout.
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	// Deployments to the same environment are queued, the lock is held until the deployment has completed, or only
+	// until it has been created if the provider does not wait for it.
+	unlock := d.lockEnvironment(ctx, *data, diags)
+	if diags.HasError() {
+		return
+	}
+	defer unlock()
+
+	d.checkRemovalLimits(ctx, *data, diags)
+	if diags.HasError() {
+		return
+	}
+
+	outputsKey := d.doDeployment(ctx, data, nonce, diags)
+	if diags.HasError() {
+		return
+	}
+	d.readDeploymentChanges(ctx, data, diags)
+	diags.Append(setDeploymentOutputsKey(ctx, private, outputsKey)...)
+	diags.Append(state.Set(ctx, data)...)
+	if data.WaitFor.ValueBool() {
+		if recordedKey := d.waitForDeployment(ctx, data, diags, outputsKey); recordedKey != outputsKey {
+			// The failed deployment was rolled back and the state records the rollback deployment now.
+			diags.Append(setDeploymentOutputsKey(ctx, private, recordedKey)...)
+		}
+		diags.Append(state.Set(ctx, data)...)
 	}
 }
 
@@ -276,14 +865,12 @@ func (d *DeploymentResource) Create(ctx context.Context, request resource.Create
 		return
 	}
 
-	outputsKey := d.doDeployment(ctx, &data, &response.Diagnostics)
+	// There is no previous deployment of this resource instance, so the idempotency key only depends on the request and
+	// a retried or resumed apply picks up the deployment of the earlier attempt.
+	d.applyDeployment(ctx, &data, "", &response.State, response.Private, &response.Diagnostics)
 	if response.Diagnostics.HasError() {
-		return
-	}
-	response.Diagnostics.Append(response.State.Set(ctx, &data)...)
-	if data.WaitFor.ValueBool() {
-		d.waitForDeployment(ctx, &data, &response.Diagnostics, outputsKey)
-		response.Diagnostics.Append(response.State.Set(ctx, &data)...)
+		// The failed create taints the resource, so that the next apply replaces it.
+		response.Diagnostics.Append(setDeploymentCreateFailed(ctx, response.Private, true)...)
 	}
 }
 
@@ -311,34 +898,418 @@ func (d *DeploymentResource) Read(ctx context.Context, request resource.ReadRequ
 		response.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to read deployment, unexpected status code: %d, body: %s", r.StatusCode(), r.Body))
 		return
 	} else {
-		// Just refresh the status/status_message/completed_at fields.
+		// An imported deployment only has its ID set, so fill in the attributes describing the deployment.
+		if data.ProjectId.IsNull() {
+			manifest, err := manifestToYaml(r.JSON200.Manifest)
+			if err != nil {
+				response.Diagnostics.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to serialize deployment manifest, got error: %s", err))
+				return
+			}
+			data.ProjectId = types.StringValue(r.JSON200.ProjectId)
+			data.EnvId = types.StringValue(r.JSON200.EnvId)
+			data.Manifest = NewDeploymentManifestValue(manifest)
+			data.Mode = types.StringValue(string(canyondp.Deploy))
+			if r.JSON200.PlanOnly {
+				data.Mode = types.StringValue(string(canyondp.PlanOnly))
+			}
+			data.CreatedAt = types.StringValue(r.JSON200.CreatedAt.Format(time.RFC3339))
+			data.RunnerId = types.StringValue(r.JSON200.RunnerId)
+		}
+
+		// Just refresh the status/status_message/completed_at/metrics fields.
 		data.Status = types.StringValue(r.JSON200.Status)
 		data.StatusMessage = types.StringValue(r.JSON200.StatusMessage)
 		data.CompletedAt = types.StringNull()
 		if r.JSON200.CompletedAt != nil {
 			data.CompletedAt = types.StringValue(r.JSON200.CompletedAt.Format(time.RFC3339))
 		}
+		metrics, diags := toDeploymentMetricsValue(ctx, r.JSON200.Metrics)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+		data.Metrics = metrics
+	}
+
+	if data.CompletedAt.IsNull() {
+		if data.WaitOnRefresh.ValueBool() {
+			readTimeout, diags := data.Timeouts.Read(ctx, DefaultAsyncTimeout)
+			response.Diagnostics.Append(diags...)
+			if response.Diagnostics.HasError() {
+				return
+			}
+			waitCtx, cancel := context.WithTimeout(ctx, readTimeout)
+			defer cancel()
+
+			tflog.Info(ctx, "Waiting for deployment to complete...", map[string]interface{}{"deployment_id": data.Id.ValueString(), "status": data.Status.ValueString()})
+			if deployment, err := d.waitForDeploymentComplete(waitCtx, deploymentUuid); err != nil {
+				response.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to wait for deployment to complete, %s", err))
+				return
+			} else {
+				data.Status = types.StringValue(deployment.Status)
+				data.StatusMessage = types.StringValue(deployment.StatusMessage)
+				data.CompletedAt = types.StringValue(deployment.CompletedAt.Format(time.RFC3339))
+				metrics, diags := toDeploymentMetricsValue(ctx, deployment.Metrics)
+				response.Diagnostics.Append(diags...)
+				if response.Diagnostics.HasError() {
+					return
+				}
+				data.Metrics = metrics
+			}
+		} else {
+			response.Diagnostics.AddWarning(
+				"Deployment not completed",
+				fmt.Sprintf("Deployment %s is still %s. Set wait_on_refresh to wait for it to complete when refreshing.", data.Id.ValueString(), data.Status.ValueString()),
+			)
+		}
+	}
+
+	// Fill in the outputs of a deployment that completed after it was applied, as long as the key it was created with
+	// is still known.
+	if data.Status.ValueString() == "succeeded" && data.Outputs.IsNull() {
+		outputsKey, diags := getDeploymentOutputsKey(ctx, request.Private)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+		if outputsKey != nil {
+			if outputs, err := fetchDeploymentOutputs(ctx, d.dpClient, d.orgId, deploymentUuid, outputsKey); err != nil {
+				response.Diagnostics.AddWarning(HUM_API_ERR, fmt.Sprintf("Unable to fetch the deployment outputs: %s", err))
+			} else {
+				data.Outputs = types.StringValue(outputs)
+			}
+		}
+	}
+
+	if policy := data.DriftPolicy.ValueString(); (policy == deploymentDriftPolicyWarn || policy == deploymentDriftPolicyRedeploy) &&
+		data.Mode.ValueString() != string(canyondp.PlanOnly) {
+		d.checkDeploymentDrift(ctx, &data, &response.Diagnostics)
+		if response.Diagnostics.HasError() {
+			return
+		}
 	}
 	response.Diagnostics.Append(response.State.Set(ctx, &data)...)
 }
 
+// checkDeploymentDrift looks for a deployment to the environment that is newer than the deployment of the model and
+// applies the drift policy if there is one.
+func (d *DeploymentResource) checkDeploymentDrift(ctx context.Context, data *DeploymentResourceModel, diags *diag.Diagnostics) {
+	createdAt, err := time.Parse(time.RFC3339, data.CreatedAt.ValueString())
+	if err != nil {
+		diags.AddWarning(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to check for newer deployments, unable to parse deployment creation time: %s", err))
+		return
+	}
+
+	latest, err := fetchLastDeployment(ctx, d.dpClient, d.orgId, data.ProjectId.ValueString(), data.EnvId.ValueString())
+	if err != nil {
+		diags.AddWarning(HUM_API_ERR, fmt.Sprintf("Unable to check for newer deployments: %s", err))
+		return
+	} else if latest == nil || latest.Id.String() == data.Id.ValueString() || latest.CreatedAt.Truncate(time.Second).Before(createdAt) {
+		return
+	}
+
+	if data.DriftPolicy.ValueString() == deploymentDriftPolicyWarn {
+		diags.AddWarning(
+			"Deployment drift detected",
+			fmt.Sprintf(
+				"Deployment %s to environment %s in project %s was created at %s, after deployment %s managed by this resource. The environment may no longer run the manifest of this resource. Set drift_policy to 'redeploy' to deploy it again.",
+				latest.Id, data.EnvId.ValueString(), data.ProjectId.ValueString(), latest.CreatedAt.Format(time.RFC3339), data.Id.ValueString(),
+			),
+		)
+		return
+	}
+
+	r, err := d.dpClient.GetDeploymentWithResponse(ctx, d.orgId, latest.Id)
+	if err != nil {
+		diags.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to read newer deployment, got error: %s", err))
+		return
+	} else if r.StatusCode() != http.StatusOK {
+		diags.AddError(HUM_API_ERR, fmt.Sprintf("Unable to read newer deployment, unexpected status code: %d, body: %s", r.StatusCode(), r.Body))
+		return
+	}
+	manifest, err := manifestToYaml(r.JSON200.Manifest)
+	if err != nil {
+		diags.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to serialize newer deployment manifest, got error: %s", err))
+		return
+	}
+
+	tflog.Info(ctx, "Newer deployment found, recording its manifest as drift", map[string]interface{}{"deployment_id": latest.Id.String()})
+	data.Manifest = NewDeploymentManifestValue(manifest)
+	// A rollback target is not part of the manifest, so clear it to have the next plan create the rollback again.
+	data.RollbackToDeploymentId = types.StringNull()
+}
+
 func (d *DeploymentResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
-	var data DeploymentResourceModel
+	var data, state DeploymentResourceModel
 	response.Diagnostics.Append(request.Plan.Get(ctx, &data)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
 	if response.Diagnostics.HasError() {
 		return
 	}
 
-	outputsKey := d.doDeployment(ctx, &data, &response.Diagnostics)
+	// A resource is only updated once it is no longer tainted, for example after terraform untaint.
+	response.Diagnostics.Append(setDeploymentCreateFailed(ctx, response.Private, false)...)
+
+	// Only settings that do not affect the deployment itself have changed, so keep the current deployment.
+	if !deploymentChanged(ctx, data, state) {
+		copyDeploymentResult(&data, state)
+		response.Diagnostics.Append(response.State.Set(ctx, &data)...)
+		return
+	}
+
+	d.applyDeployment(ctx, &data, state.Id.ValueString(), &response.State, response.Private, &response.Diagnostics)
+}
+
+func (d *DeploymentResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var data DeploymentResourceModel
+	response.Diagnostics.Append(request.State.Get(ctx, &data)...)
 	if response.Diagnostics.HasError() {
 		return
 	}
-	response.Diagnostics.Append(response.State.Set(ctx, &data)...)
-	if data.WaitFor.ValueBool() {
-		d.waitForDeployment(ctx, &data, &response.Diagnostics, outputsKey)
-		response.Diagnostics.Append(response.State.Set(ctx, &data)...)
+
+	// A plan only deployment did not change the environment, so there is nothing to undo.
+	if data.Mode.ValueString() == string(canyondp.PlanOnly) {
+		return
+	}
+
+	// A failed create taints the resource and the next apply replaces it, also when rollback_on_failure recorded the
+	// rollback. Tearing down the environment right before deploying the same manifest again would only destroy its
+	// resources, databases included.
+	createFailed, dd := getDeploymentCreateFailed(ctx, request.Private)
+	response.Diagnostics.Append(dd...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	if createFailed && data.OnDestroy.ValueString() != deploymentOnDestroyNone {
+		response.Diagnostics.AddWarning(
+			"Skipping on_destroy",
+			fmt.Sprintf("The create of deployment %s failed, so on_destroy '%s' is not deployed to environment %s in project %s.", data.Id.ValueString(), data.OnDestroy.ValueString(), data.EnvId.ValueString(), data.ProjectId.ValueString()),
+		)
+		return
+	}
+
+	var manifest canyondp.DeploymentManifest
+	switch data.OnDestroy.ValueString() {
+	case deploymentOnDestroyRollback:
+		previous, err := d.findPreviousDeployment(ctx, data)
+		if err != nil {
+			response.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to find the previous deployment, got error: %s", err))
+			return
+		} else if previous == nil {
+			response.Diagnostics.AddWarning(HUM_RESOURCE_NOT_FOUND_ERR, fmt.Sprintf("No successful deployment to environment %s in project %s found before deployment %s, nothing to roll back to.", data.EnvId.ValueString(), data.ProjectId.ValueString(), data.Id.ValueString()))
+			return
+		}
+		if r, err := d.dpClient.GetDeploymentWithResponse(ctx, d.orgId, previous.Id); err != nil {
+			response.Diagnostics.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to read previous deployment, got error: %s", err))
+			return
+		} else if r.StatusCode() != http.StatusOK {
+			response.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to read previous deployment, unexpected status code: %d, body: %s", r.StatusCode(), r.Body))
+			return
+		} else {
+			manifest = r.JSON200.Manifest
+		}
+		tflog.Info(ctx, "Rolling back environment to the previous deployment", map[string]interface{}{"deployment_id": previous.Id.String()})
+	case deploymentOnDestroyEmpty:
+		manifest = canyondp.DeploymentManifest{Workloads: map[string]canyondp.DeploymentManifestWorkload{}}
+		tflog.Info(ctx, "Deploying an empty manifest to the environment")
+	default:
+		return
 	}
+
+	rawManifest, err := manifestToYaml(manifest)
+	if err != nil {
+		response.Diagnostics.AddError(HUM_PROVIDER_ERR, fmt.Sprintf("Unable to serialize deployment manifest, got error: %s", err))
+		return
+	}
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, DefaultAsyncTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	teardown := DeploymentResourceModel{
+		ProjectId:       data.ProjectId,
+		EnvId:           data.EnvId,
+		Manifest:        NewDeploymentManifestValue(rawManifest),
+		Mode:            types.StringValue(string(canyondp.Deploy)),
+		RunnerLogLevel:  data.RunnerLogLevel,
+		FailureLogLines: data.FailureLogLines,
+		Timeouts:        data.Timeouts,
+	}
+	// Waiting for the lock and the deployment count towards the same timeout.
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	unlock := d.lockEnvironment(ctx, data, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	defer unlock()
+
+	outputsKey := d.doDeployment(ctx, &teardown, data.Id.ValueString(), &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	d.waitForDeployment(ctx, &teardown, &response.Diagnostics, outputsKey)
 }
 
-func (d *DeploymentResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+func (d *DeploymentResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	// Import format: deployment_id
+	if _, err := uuid.Parse(request.ID); err != nil {
+		response.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: deployment_id (a UUID). Got: %q", request.ID),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("id"), request.ID)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("wait_for"), true)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("failure_log_lines"), defaultDeploymentFailureLogLines)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("on_destroy"), deploymentOnDestroyNone)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("wait_on_refresh"), false)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("rollback_on_failure"), false)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("drift_policy"), deploymentDriftPolicyIgnore)...)
+}
+
+// findPreviousDeployment returns the latest successful deployment to the same environment that was created before
+// the deployment of the model, or nil if there is none.
+func (d *DeploymentResource) findPreviousDeployment(ctx context.Context, data DeploymentResourceModel) (*canyondp.DeploymentSummary, error) {
+	createdAt, err := time.Parse(time.RFC3339, data.CreatedAt.ValueString())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse deployment creation time: %w", err)
+	}
+
+	var previous *canyondp.DeploymentSummary
+	var pageCursor *string
+	for {
+		r, err := d.dpClient.ListDeploymentsWithResponse(ctx, d.orgId, &canyondp.ListDeploymentsParams{
+			ProjectId: data.ProjectId.ValueStringPointer(),
+			EnvId:     data.EnvId.ValueStringPointer(),
+			Page:      pageCursor,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list deployments, got error: %w", err)
+		} else if r.StatusCode() != http.StatusOK {
+			return nil, fmt.Errorf("unable to list deployments, unexpected status code: %d, body: %s", r.StatusCode(), r.Body)
+		}
+
+		for _, item := range r.JSON200.Items {
+			// The creation time in the state is truncated to seconds.
+			if item.Id.String() == data.Id.ValueString() || item.Status != "succeeded" || item.PlanOnly ||
+				item.CreatedAt.Truncate(time.Second).After(createdAt) {
+				continue
+			}
+			if previous == nil || item.CreatedAt.After(previous.CreatedAt) {
+				previous = &item
+			}
+		}
+
+		if r.JSON200.NextPageToken == nil {
+			break
+		}
+		pageCursor = r.JSON200.NextPageToken
+	}
+	return previous, nil
+}
+
+// deploymentChanged returns true if the planned model requires a new deployment compared to the prior state. The
+// structured workloads are covered by the manifest, which is built from them during planning, and manifests are
+// compared semantically so that switching between YAML, JSON, and the structured workloads does not redeploy.
+func deploymentChanged(ctx context.Context, plan, state DeploymentResourceModel) bool {
+	return !deploymentManifestsEqual(ctx, plan.Manifest, state.Manifest) ||
+		!plan.RollbackToDeploymentId.Equal(state.RollbackToDeploymentId) ||
+		!plan.Mode.Equal(state.Mode)
+}
+
+// copyDeploymentResult copies the computed attributes describing the current deployment from the prior state.
+func copyDeploymentResult(data *DeploymentResourceModel, state DeploymentResourceModel) {
+	data.Manifest = state.Manifest
+	data.Id = state.Id
+	data.CreatedAt = state.CreatedAt
+	data.CompletedAt = state.CompletedAt
+	data.Status = state.Status
+	data.StatusMessage = state.StatusMessage
+	data.RunnerId = state.RunnerId
+	data.Outputs = state.Outputs
+	data.Metrics = state.Metrics
+	data.PlannedChanges = state.PlannedChanges
+}
+
+// toDeploymentCreateBody builds the create request for the deployment described by the model.
+func toDeploymentCreateBody(ctx context.Context, data DeploymentResourceModel) (canyondp.DeploymentCreateBody, error) {
+	body := canyondp.DeploymentCreateBody{
+		ProjectId: data.ProjectId.ValueString(),
+		EnvId:     data.EnvId.ValueString(),
+		Mode:      canyondp.DeploymentCreateBodyMode(data.Mode.ValueString()),
+	}
+
+	if !data.RollbackToDeploymentId.IsNull() {
+		rollbackToId, err := uuid.Parse(data.RollbackToDeploymentId.ValueString())
+		if err != nil {
+			return body, fmt.Errorf("unable to parse rollback deployment ID, got error: %w", err)
+		}
+		body.Mode = canyondp.Rollback
+		body.RollbackToDeploymentId = &rollbackToId
+		body.PlanOnly = ref.Ref(data.Mode.ValueString() == string(canyondp.PlanOnly))
+	} else if !data.Workloads.IsNull() {
+		manifest, err := toDeploymentManifestFromModel(ctx, data.Workloads, data.Shared)
+		if err != nil {
+			return body, fmt.Errorf("unable to build manifest, got error: %w", err)
+		}
+		body.Manifest = &manifest
+	} else {
+		manifest, err := data.Manifest.ToDeploymentManifest()
+		if err != nil {
+			return body, fmt.Errorf("unable to parse manifest, got error: %w", err)
+		}
+		body.Manifest = &manifest
+	}
+
+	if !data.RunnerLogLevel.IsNull() {
+		body.RunnerLogLevel = ref.Ref(canyondp.DeploymentCreateBodyRunnerLogLevel(data.RunnerLogLevel.ValueString()))
+	}
+	return body, nil
+}
+
+// manifestToYaml serializes a deployment manifest into YAML, omitting empty fields the same way the API does.
+func manifestToYaml(manifest canyondp.DeploymentManifest) (string, error) {
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(generic)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// rollbackManifestPlanModifier marks the computed manifest as unknown when the rollback target changes, since the
+// effective manifest is only known once the rollback deployment has been created.
+type rollbackManifestPlanModifier struct{}
+
+func (m rollbackManifestPlanModifier) Description(ctx context.Context) string {
+	return "Marks the manifest as unknown when the rollback target changes."
+}
+
+func (m rollbackManifestPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m rollbackManifestPlanModifier) PlanModifyString(ctx context.Context, request planmodifier.StringRequest, response *planmodifier.StringResponse) {
+	if request.State.Raw.IsNull() || !request.ConfigValue.IsNull() {
+		return
+	}
+
+	var planRollbackToId, stateRollbackToId types.String
+	response.Diagnostics.Append(request.Plan.GetAttribute(ctx, path.Root("rollback_to_deployment_id"), &planRollbackToId)...)
+	response.Diagnostics.Append(request.State.GetAttribute(ctx, path.Root("rollback_to_deployment_id"), &stateRollbackToId)...)
+	if !planRollbackToId.Equal(stateRollbackToId) {
+		response.PlanValue = types.StringUnknown()
+	}
 }
@@ -1,12 +1,35 @@
 package provider
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
 	"regexp"
+	"strconv"
 	"testing"
+	"time"
 
+	"filippo.io/age"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+	"terraform-provider-humanitec-v2/internal/ref"
 )
 
 const deploymentScenario = `
@@ -124,3 +147,1211 @@ resource "platform-orchestrator_deployment" "deployment" {
 		},
 	})
 }
+
+func TestAccDeploymentResource_rollback_conflicts_with_manifest(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "platform-orchestrator_deployment" "deployment" {
+  project_id                = "does-not-exist"
+  env_id                    = "does-not-exist"
+  rollback_to_deployment_id = "00000000-0000-0000-0000-000000000000"
+  manifest = jsonencode({
+    workloads = {}
+  })
+}
+`, ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentResource_rollback_bad_request(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "platform-orchestrator_deployment" "deployment" {
+  project_id                = "does-not-exist"
+  env_id                    = "does-not-exist"
+  rollback_to_deployment_id = "00000000-0000-0000-0000-000000000000"
+}
+`, ExpectError: regexp.MustCompile(`Unable to create deployment, unexpected status code: 4\d\d.*`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentResource_workloads_bad_request(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "platform-orchestrator_deployment" "deployment" {
+  project_id = "does-not-exist"
+  env_id     = "does-not-exist"
+  workloads = {
+    main = {
+      resources = {
+        db = {
+          type   = "postgres"
+          params = jsonencode({ version = "16" })
+        }
+      }
+      outputs = {
+        url = "$${resources.db.outputs.url}"
+      }
+    }
+  }
+  shared = {
+    dns = {
+      type = "dns"
+      id   = "shared.dns"
+    }
+  }
+}
+`, ExpectError: regexp.MustCompile(`Unable to create deployment, unexpected status code: 409.*`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentResource_workloads_invalid_resource_type(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "platform-orchestrator_deployment" "deployment" {
+  project_id = "does-not-exist"
+  env_id     = "does-not-exist"
+  workloads = {
+    main = {
+      resources = {
+        db = {
+          type = "not_a_valid_type"
+        }
+      }
+    }
+  }
+}
+`, ExpectError: regexp.MustCompile(`must be a valid resource type`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentResource_invalid_on_destroy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "platform-orchestrator_deployment" "deployment" {
+  project_id = "does-not-exist"
+  env_id     = "does-not-exist"
+  on_destroy = "delete"
+  manifest = jsonencode({
+    workloads = {}
+  })
+}
+`, ExpectError: regexp.MustCompile(`Attribute on_destroy value must be one of`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentResource_import_invalid_id(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "platform-orchestrator_deployment" "deployment" {
+  project_id = "does-not-exist"
+  env_id     = "does-not-exist"
+  manifest = jsonencode({
+    workloads = {}
+  })
+}
+`,
+				ResourceName:  "platform-orchestrator_deployment.deployment",
+				ImportState:   true,
+				ImportStateId: "not-a-uuid",
+				ExpectError:   regexp.MustCompile(`Unexpected Import Identifier`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentResource_invalid_drift_policy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "platform-orchestrator_deployment" "deployment" {
+  project_id   = "does-not-exist"
+  env_id       = "does-not-exist"
+  drift_policy = "revert"
+  manifest = jsonencode({
+    workloads = {}
+  })
+}
+`, ExpectError: regexp.MustCompile(`Attribute drift_policy value must be one of`),
+			},
+		},
+	})
+}
+
+func TestAccDeploymentResource_invalid_max_removed(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "platform-orchestrator_deployment" "deployment" {
+  project_id        = "does-not-exist"
+  env_id            = "does-not-exist"
+  max_removed_nodes = -1
+  manifest = jsonencode({
+    workloads = {}
+  })
+}
+`, ExpectError: regexp.MustCompile(`Attribute max_removed_nodes value must be at least 0`),
+			},
+		},
+	})
+}
+
+// fakeDeploymentClient answers the deployment endpoints from memory the way the API does. Calls to any other endpoint
+// panic through the embedded nil client.
+type fakeDeploymentClient struct {
+	canyondp.ClientWithResponsesInterface
+
+	// deployments holds the deployments that were created, or that exist beforehand, in creation order.
+	deployments []canyondp.Deployment
+	// idempotencyKeys maps the idempotency keys of the created deployments to their index in deployments. As described in
+	// the API spec, a key only returns the existing deployment while it is the last deployment to its environment, and
+	// is not accepted again within 24 hours afterwards, which the fake answers with an idempotency key conflict.
+	idempotencyKeys map[string]int
+	// status is the status deployments complete with, unless statuses has one for the deployment.
+	status   string
+	statuses map[uuid.UUID]string
+	// failures is the number of deployments that complete with status failed before the others complete with status.
+	failures int
+	// conflicts is the number of create requests rejected with a conflict that is not about the idempotency key.
+	conflicts int
+	// removedResources is the number of removed Terraform resources reported by the completed deployments.
+	removedResources *int
+	// diff is returned for dry-run deployments and when calculating the diff of a deployment.
+	diff canyondp.DeploymentDiff
+	// diffStatus is the status code returned when calculating the diff of a deployment, if not 0.
+	diffStatus int
+	// pageSize is the number of deployments returned per page when listing deployments, all of them if 0.
+	pageSize int
+	// logs are the runner logs returned for any deployment.
+	logs []byte
+	// outputsRecipients maps the created deployments to the recipient their empty outputs are encrypted for.
+	outputsRecipients map[uuid.UUID]string
+
+	created    []canyondp.DeploymentCreateBody
+	logsParams []canyondp.GetDeploymentLogsParams
+	// waitDeadline is the deadline of the context of the last wait for a deployment to complete.
+	waitDeadline time.Time
+}
+
+func fakeHttpResponse(statusCode int) *http.Response {
+	return &http.Response{StatusCode: statusCode, Header: http.Header{}}
+}
+
+func (c *fakeDeploymentClient) find(deploymentId uuid.UUID) *canyondp.Deployment {
+	for i := range c.deployments {
+		if c.deployments[i].Id == deploymentId {
+			return &c.deployments[i]
+		}
+	}
+	return nil
+}
+
+func (c *fakeDeploymentClient) CreateDeploymentWithResponse(_ context.Context, orgId string, params *canyondp.CreateDeploymentParams, body canyondp.CreateDeploymentJSONRequestBody, _ ...canyondp.RequestEditorFn) (*canyondp.CreateDeploymentResponse, error) {
+	if body.IsDryRun {
+		return &canyondp.CreateDeploymentResponse{HTTPResponse: fakeHttpResponse(http.StatusOK), JSON200: &canyondp.DeploymentDryRun{Diff: c.diff}}, nil
+	}
+	if c.idempotencyKeys == nil {
+		c.idempotencyKeys = map[string]int{}
+	}
+	if c.conflicts > 0 {
+		c.conflicts--
+		conflict := &canyondp.N409Conflict{Error: "API-409", Message: "environment does not exist"}
+		return &canyondp.CreateDeploymentResponse{HTTPResponse: fakeHttpResponse(http.StatusConflict), JSON409: conflict, Body: []byte(conflict.Message)}, nil
+	}
+	if params.IdempotencyKey != nil {
+		if i, ok := c.idempotencyKeys[*params.IdempotencyKey]; ok && c.isLast(i) {
+			return &canyondp.CreateDeploymentResponse{HTTPResponse: fakeHttpResponse(http.StatusCreated), JSON201: &c.deployments[i]}, nil
+		} else if ok {
+			conflict := &canyondp.N409Conflict{Error: "API-409", Message: "idempotency key has already been used"}
+			return &canyondp.CreateDeploymentResponse{HTTPResponse: fakeHttpResponse(http.StatusConflict), JSON409: conflict, Body: []byte(conflict.Message)}, nil
+		}
+	}
+
+	c.created = append(c.created, body)
+	deployment := canyondp.Deployment{
+		Id:                     uuid.New(),
+		OrgId:                  orgId,
+		ProjectId:              body.ProjectId,
+		EnvId:                  body.EnvId,
+		Mode:                   string(body.Mode),
+		PlanOnly:               body.PlanOnly != nil && *body.PlanOnly,
+		RollbackToDeploymentId: body.RollbackToDeploymentId,
+		Status:                 "in_progress",
+		CreatedAt:              time.Now(),
+	}
+	if body.Manifest != nil {
+		deployment.Manifest = *body.Manifest
+	} else if body.RollbackToDeploymentId != nil {
+		if target := c.find(*body.RollbackToDeploymentId); target != nil {
+			deployment.Manifest = target.Manifest
+		}
+	}
+	c.deployments = append(c.deployments, deployment)
+	if params.IdempotencyKey != nil {
+		c.idempotencyKeys[*params.IdempotencyKey] = len(c.deployments) - 1
+	}
+	if body.EncryptedOutputsRecipient != nil {
+		if c.outputsRecipients == nil {
+			c.outputsRecipients = map[uuid.UUID]string{}
+		}
+		c.outputsRecipients[deployment.Id] = *body.EncryptedOutputsRecipient
+	}
+	return &canyondp.CreateDeploymentResponse{HTTPResponse: fakeHttpResponse(http.StatusCreated), JSON201: &deployment}, nil
+}
+
+// isLast returns true if no later deployment was created to the environment of the deployment at the index.
+func (c *fakeDeploymentClient) isLast(i int) bool {
+	for _, deployment := range c.deployments[i+1:] {
+		if deployment.ProjectId == c.deployments[i].ProjectId && deployment.EnvId == c.deployments[i].EnvId {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *fakeDeploymentClient) GetDeploymentWithResponse(_ context.Context, _ string, deploymentId uuid.UUID, _ ...canyondp.RequestEditorFn) (*canyondp.GetDeploymentResponse, error) {
+	if deployment := c.find(deploymentId); deployment != nil {
+		return &canyondp.GetDeploymentResponse{HTTPResponse: fakeHttpResponse(http.StatusOK), JSON200: deployment}, nil
+	}
+	return &canyondp.GetDeploymentResponse{HTTPResponse: fakeHttpResponse(http.StatusNotFound)}, nil
+}
+
+func (c *fakeDeploymentClient) WaitForDeploymentCompleteWithResponse(ctx context.Context, _ string, deploymentId uuid.UUID, _ *canyondp.WaitForDeploymentCompleteParams, _ ...canyondp.RequestEditorFn) (*canyondp.WaitForDeploymentCompleteResponse, error) {
+	c.waitDeadline, _ = ctx.Deadline()
+	deployment := c.find(deploymentId)
+	if deployment == nil {
+		return &canyondp.WaitForDeploymentCompleteResponse{HTTPResponse: fakeHttpResponse(http.StatusNotFound)}, nil
+	}
+	if deployment.CompletedAt == nil {
+		deployment.Status = c.status
+		if status, ok := c.statuses[deploymentId]; ok {
+			deployment.Status = status
+		} else if c.failures > 0 {
+			deployment.Status = "failed"
+			c.failures--
+		}
+		deployment.CompletedAt = ref.Ref(time.Now())
+		deployment.Metrics.NumTfResourcesRemoved = c.removedResources
+	}
+	return &canyondp.WaitForDeploymentCompleteResponse{HTTPResponse: fakeHttpResponse(http.StatusOK), JSON200: deployment}, nil
+}
+
+func (c *fakeDeploymentClient) GetDeploymentEncryptedOutputsWithResponse(_ context.Context, _ string, deploymentId uuid.UUID, _ ...canyondp.RequestEditorFn) (*canyondp.GetDeploymentEncryptedOutputsResponse, error) {
+	recipient, ok := c.outputsRecipients[deploymentId]
+	if !ok {
+		return &canyondp.GetDeploymentEncryptedOutputsResponse{HTTPResponse: fakeHttpResponse(http.StatusNotFound)}, nil
+	}
+	parsed, err := age.ParseX25519Recipient(recipient)
+	if err != nil {
+		return nil, err
+	}
+	var raw bytes.Buffer
+	encoder := base64.NewEncoder(base64.StdEncoding, &raw)
+	w, err := age.Encrypt(encoder, parsed)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = w.Write([]byte("{}"))
+	_ = w.Close()
+	_ = encoder.Close()
+	return &canyondp.GetDeploymentEncryptedOutputsResponse{
+		HTTPResponse: fakeHttpResponse(http.StatusOK),
+		JSON200:      &canyondp.DeploymentEncryptedOutputs{Raw: raw.String()},
+	}, nil
+}
+
+// summaries returns the deployments in the project and environment, or in any of them if nil.
+func (c *fakeDeploymentClient) summaries(projectId, envId *string) []canyondp.DeploymentSummary {
+	items := make([]canyondp.DeploymentSummary, 0)
+	for _, deployment := range c.deployments {
+		if (projectId == nil || deployment.ProjectId == *projectId) && (envId == nil || deployment.EnvId == *envId) {
+			items = append(items, canyondp.DeploymentSummary{
+				Id:        deployment.Id,
+				ProjectId: deployment.ProjectId,
+				EnvId:     deployment.EnvId,
+				Mode:      deployment.Mode,
+				PlanOnly:  deployment.PlanOnly,
+				Status:    deployment.Status,
+				CreatedAt: deployment.CreatedAt,
+			})
+		}
+	}
+	return items
+}
+
+func (c *fakeDeploymentClient) ListDeploymentsWithResponse(_ context.Context, _ string, params *canyondp.ListDeploymentsParams, _ ...canyondp.RequestEditorFn) (*canyondp.ListDeploymentsResponse, error) {
+	items := c.summaries(params.ProjectId, params.EnvId)
+	page := &canyondp.DeploymentPage{Items: items}
+	if c.pageSize > 0 {
+		start := 0
+		if params.Page != nil {
+			start, _ = strconv.Atoi(*params.Page)
+		}
+		end := min(start+c.pageSize, len(items))
+		page.Items = items[start:end]
+		if end < len(items) {
+			page.NextPageToken = ref.Ref(strconv.Itoa(end))
+		}
+	}
+	return &canyondp.ListDeploymentsResponse{HTTPResponse: fakeHttpResponse(http.StatusOK), JSON200: page}, nil
+}
+
+func (c *fakeDeploymentClient) ListLastDeploymentsWithResponse(_ context.Context, _ string, params *canyondp.ListLastDeploymentsParams, _ ...canyondp.RequestEditorFn) (*canyondp.ListLastDeploymentsResponse, error) {
+	var latest []canyondp.DeploymentSummary
+	for _, item := range c.summaries(params.ProjectId, params.EnvId) {
+		if !item.PlanOnly && (len(latest) == 0 || item.CreatedAt.After(latest[0].CreatedAt)) {
+			latest = []canyondp.DeploymentSummary{item}
+		}
+	}
+	return &canyondp.ListLastDeploymentsResponse{HTTPResponse: fakeHttpResponse(http.StatusOK), JSON200: &canyondp.DeploymentPage{Items: latest}}, nil
+}
+
+func (c *fakeDeploymentClient) CalculateDeploymentDiffWithResponse(_ context.Context, _ string, _ uuid.UUID, _ *canyondp.CalculateDeploymentDiffParams, _ ...canyondp.RequestEditorFn) (*canyondp.CalculateDeploymentDiffResponse, error) {
+	if c.diffStatus != 0 {
+		return &canyondp.CalculateDeploymentDiffResponse{HTTPResponse: fakeHttpResponse(c.diffStatus)}, nil
+	}
+	return &canyondp.CalculateDeploymentDiffResponse{HTTPResponse: fakeHttpResponse(http.StatusOK), JSON200: &c.diff}, nil
+}
+
+func (c *fakeDeploymentClient) GetDeploymentLogsWithResponse(_ context.Context, _ string, _ uuid.UUID, params *canyondp.GetDeploymentLogsParams, _ ...canyondp.RequestEditorFn) (*canyondp.GetDeploymentLogsResponse, error) {
+	c.logsParams = append(c.logsParams, *params)
+	return &canyondp.GetDeploymentLogsResponse{HTTPResponse: fakeHttpResponse(http.StatusOK), Body: c.logs}, nil
+}
+
+// testDeploymentResourceValue returns the raw value of a deployment resource with the given attributes set, all other
+// attributes are null.
+func testDeploymentResourceValue(t *testing.T, attributes map[string]interface{}) tftypes.Value {
+	s := testDeploymentResourceSchema()
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+	for name, value := range attributes {
+		require.False(t, state.SetAttribute(context.Background(), path.Root(name), value).HasError(), name)
+	}
+	return state.Raw
+}
+
+// testInitPrivateState sets the private state of a framework response to an empty one. Its type is internal to the
+// framework, so it can not be created directly.
+func testInitPrivateState(private interface{}) {
+	value := reflect.ValueOf(private).Elem()
+	value.Set(reflect.New(value.Type().Elem()))
+}
+
+func testDeploymentResourceSchema() fwschema.Schema {
+	var schemaResponse fwresource.SchemaResponse
+	(&DeploymentResource{}).Schema(context.Background(), fwresource.SchemaRequest{}, &schemaResponse)
+	return schemaResponse.Schema
+}
+
+func testDeploymentResourceModel(t *testing.T, attributes map[string]interface{}) DeploymentResourceModel {
+	var data DeploymentResourceModel
+	state := tfsdk.State{Schema: testDeploymentResourceSchema(), Raw: testDeploymentResourceValue(t, attributes)}
+	require.False(t, state.Get(context.Background(), &data).HasError())
+	return data
+}
+
+var testDeploymentManifest = `{"workloads":{"main":{"variables":{"ANIMAL":"cat"}}}}`
+
+func TestDeploymentResourceModifyPlan_planned_changes(t *testing.T) {
+	client := &fakeDeploymentClient{diff: canyondp.DeploymentDiff{
+		NumAdded: 1,
+		Changes: []canyondp.DeploymentDiffChange{
+			{Id: "abc", Resource: "workload.main", Type: canyondp.DeploymentDiffChangeTypeAdded, Summary: "added"},
+		},
+	}}
+	d := &DeploymentResource{dpClient: client, orgId: "my-org"}
+
+	plan := tfsdk.Plan{Schema: testDeploymentResourceSchema(), Raw: testDeploymentResourceValue(t, map[string]interface{}{
+		"project_id":      "my-project",
+		"env_id":          "development",
+		"manifest":        testDeploymentManifest,
+		"mode":            "deploy",
+		"planned_changes": types.ListUnknown(types.ObjectType{AttrTypes: deploymentDiffChangeAttributeTypes()}),
+	})}
+	request := fwresource.ModifyPlanRequest{
+		Plan:  plan,
+		State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}
+	response := fwresource.ModifyPlanResponse{Plan: plan}
+	d.ModifyPlan(context.Background(), request, &response)
+	require.False(t, response.Diagnostics.HasError(), response.Diagnostics)
+
+	// The preview is a warning only, the planned value stays unknown so that it can not change at apply time.
+	var planned types.List
+	require.False(t, response.Plan.GetAttribute(context.Background(), path.Root("planned_changes"), &planned).HasError())
+	assert.True(t, planned.IsUnknown())
+	require.Len(t, response.Diagnostics.Warnings(), 1)
+	assert.Contains(t, response.Diagnostics.Warnings()[0].Detail(), "added workload.main: added")
+}
+
+func TestDeploymentResourceReadDeploymentChanges(t *testing.T) {
+	client := &fakeDeploymentClient{diff: canyondp.DeploymentDiff{
+		Changes: []canyondp.DeploymentDiffChange{
+			{Id: "abc", Resource: "workload.main", Type: canyondp.DeploymentDiffChangeTypeParamsChanged, Summary: "changed"},
+		},
+	}}
+	d := &DeploymentResource{dpClient: client, orgId: "my-org"}
+
+	data := testDeploymentResourceModel(t, map[string]interface{}{"id": uuid.NewString()})
+	var diags diag.Diagnostics
+	d.readDeploymentChanges(context.Background(), &data, &diags)
+	require.False(t, diags.HasError(), diags)
+
+	var changes []DeploymentDiffChangeModel
+	require.False(t, data.PlannedChanges.ElementsAs(context.Background(), &changes, false).HasError())
+	require.Len(t, changes, 1)
+	assert.Equal(t, "params_changed", changes[0].Type.ValueString())
+	assert.Equal(t, "workload.main", changes[0].Resource.ValueString())
+}
+
+func TestDeploymentResourceWaitForDeployment_failure_logs(t *testing.T) {
+	client := &fakeDeploymentClient{status: "failed"}
+	d := &DeploymentResource{dpClient: client, orgId: "my-org"}
+
+	data := testDeploymentResourceModel(t, map[string]interface{}{
+		"project_id":        "my-project",
+		"env_id":            "development",
+		"manifest":          testDeploymentManifest,
+		"failure_log_lines": 2,
+	})
+	var diags diag.Diagnostics
+	outputsKey := d.doDeployment(context.Background(), &data, "", &diags)
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, outputsKey.Recipient().String(), *client.created[0].EncryptedLogsRecipient)
+
+	var logs bytes.Buffer
+	w, err := age.Encrypt(&logs, outputsKey.Recipient())
+	require.NoError(t, err)
+	_, err = w.Write([]byte("line 1\nline 2\nline 3\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	client.logs = logs.Bytes()
+
+	d.waitForDeployment(context.Background(), &data, &diags, outputsKey)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags.Errors()[0].Detail(), "Last 2 lines of the runner logs:\nline 2\nline 3")
+	// The logs are decrypted by the provider, the key must never be sent to the API.
+	require.Len(t, client.logsParams, 1)
+	assert.Nil(t, client.logsParams[0].DecryptKey)
+}
+
+func TestDeploymentResourceCreate_idempotency(t *testing.T) {
+	attributes := map[string]interface{}{
+		"project_id": "my-project",
+		"env_id":     "development",
+		"manifest":   testDeploymentManifest,
+		"mode":       "deploy",
+	}
+	create := func(d *DeploymentResource, attributes map[string]interface{}) (tfsdk.State, DeploymentResourceModel, diag.Diagnostics) {
+		plan := tfsdk.Plan{Schema: testDeploymentResourceSchema(), Raw: testDeploymentResourceValue(t, attributes)}
+		response := fwresource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw.Copy()}}
+		testInitPrivateState(&response.Private)
+		d.Create(context.Background(), fwresource.CreateRequest{Plan: plan}, &response)
+		var data DeploymentResourceModel
+		require.False(t, response.State.Get(context.Background(), &data).HasError())
+		return response.State, data, response.Diagnostics
+	}
+
+	t.Run("resumed apply", func(t *testing.T) {
+		client := &fakeDeploymentClient{status: "succeeded"}
+		d := &DeploymentResource{dpClient: client, orgId: "my-org", locks: NewDeploymentLocks()}
+
+		_, first, diags := create(d, attributes)
+		require.False(t, diags.HasError(), diags)
+		// The apply is resumed, for example because it was interrupted before Terraform saved the state.
+		_, second, diags := create(d, attributes)
+		require.False(t, diags.HasError(), diags)
+		require.Len(t, client.created, 1)
+		assert.Equal(t, first.Id.ValueString(), second.Id.ValueString())
+		// The outputs were encrypted for the earlier attempt.
+		require.Len(t, diags.Warnings(), 1)
+		assert.Contains(t, diags.Warnings()[0].Detail(), "is picked up instead of deploying again")
+		assert.True(t, second.Outputs.IsNull())
+	})
+
+	t.Run("other conflict", func(t *testing.T) {
+		client := &fakeDeploymentClient{status: "succeeded", conflicts: 1}
+		d := &DeploymentResource{dpClient: client, orgId: "my-org", locks: NewDeploymentLocks()}
+
+		// A conflict that is not about the idempotency key, like a missing environment, is not retried.
+		_, _, diags := create(d, attributes)
+		require.Len(t, diags.Errors(), 1)
+		assert.Contains(t, diags.Errors()[0].Detail(), "Unable to create deployment, unexpected status code: 409")
+		assert.Empty(t, client.created)
+	})
+
+	t.Run("failed predecessor", func(t *testing.T) {
+		client := &fakeDeploymentClient{status: "failed"}
+		d := &DeploymentResource{dpClient: client, orgId: "my-org", locks: NewDeploymentLocks()}
+
+		_, first, diags := create(d, attributes)
+		require.True(t, diags.HasError())
+		assert.Equal(t, "failed", first.Status.ValueString())
+		// The failed deployment taints the resource, recreating it with the same manifest must deploy again.
+		_, second, _ := create(d, attributes)
+		require.Len(t, client.created, 2)
+		assert.NotEqual(t, first.Id.ValueString(), second.Id.ValueString())
+	})
+
+	t.Run("rollback_on_failure then re-apply", func(t *testing.T) {
+		previous := canyondp.Deployment{
+			Id: uuid.New(), ProjectId: "my-project", EnvId: "development", Status: "succeeded", CreatedAt: time.Now().Add(-time.Minute),
+		}
+		client := &fakeDeploymentClient{deployments: []canyondp.Deployment{previous}, status: "succeeded", failures: 1}
+		d := &DeploymentResource{dpClient: client, orgId: "my-org", locks: NewDeploymentLocks()}
+		withRollback := map[string]interface{}{"rollback_on_failure": true}
+		for k, v := range attributes {
+			withRollback[k] = v
+		}
+
+		_, first, diags := create(d, withRollback)
+		require.True(t, diags.HasError())
+		require.Len(t, client.created, 2)
+		assert.Equal(t, previous.Id.String(), first.RollbackToDeploymentId.ValueString())
+		// The rollback is now the last deployment, so the idempotency key of the failed deployment is rejected.
+		_, second, diags := create(d, withRollback)
+		require.False(t, diags.HasError(), diags)
+		require.Len(t, client.created, 3)
+		assert.Equal(t, client.deployments[3].Id.String(), second.Id.ValueString())
+		assert.Equal(t, "succeeded", second.Status.ValueString())
+	})
+
+	t.Run("on_destroy empty then recreate", func(t *testing.T) {
+		client := &fakeDeploymentClient{status: "succeeded"}
+		d := &DeploymentResource{dpClient: client, orgId: "my-org", locks: NewDeploymentLocks()}
+		withTeardown := map[string]interface{}{"on_destroy": deploymentOnDestroyEmpty}
+		for k, v := range attributes {
+			withTeardown[k] = v
+		}
+
+		state, first, diags := create(d, withTeardown)
+		require.False(t, diags.HasError(), diags)
+		response := fwresource.DeleteResponse{State: state}
+		d.Delete(context.Background(), fwresource.DeleteRequest{State: state}, &response)
+		require.False(t, response.Diagnostics.HasError(), response.Diagnostics)
+		require.Len(t, client.created, 2)
+		// The teardown is now the last deployment, so the idempotency key of the first deployment is rejected.
+		_, second, diags := create(d, withTeardown)
+		require.False(t, diags.HasError(), diags)
+		require.Len(t, client.created, 3)
+		assert.NotEqual(t, first.Id.ValueString(), second.Id.ValueString())
+		assert.Equal(t, testDeploymentManifest, second.Manifest.ValueString())
+	})
+}
+
+func TestDeploymentResourceCreate_timeout(t *testing.T) {
+	client := &fakeDeploymentClient{status: "succeeded"}
+	d := &DeploymentResource{dpClient: client, orgId: "my-org", locks: NewDeploymentLocks()}
+
+	plan := tfsdk.Plan{Schema: testDeploymentResourceSchema(), Raw: testDeploymentResourceValue(t, map[string]interface{}{
+		"project_id": "my-project",
+		"env_id":     "development",
+		"manifest":   testDeploymentManifest,
+		"mode":       "deploy",
+		"wait_for":   true,
+	})}
+	require.False(t, plan.SetAttribute(context.Background(), path.Root("timeouts").AtName("create"), "1s").HasError())
+
+	// Another deployment to the environment holds the lock for a while.
+	unlock, err := d.locks.Lock(context.Background(), "my-project", "development")
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		unlock()
+	}()
+
+	start := time.Now()
+	response := fwresource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw.Copy()}}
+	testInitPrivateState(&response.Private)
+	d.Create(context.Background(), fwresource.CreateRequest{Plan: plan}, &response)
+	require.False(t, response.Diagnostics.HasError(), response.Diagnostics)
+	require.Len(t, client.created, 1)
+
+	// The time spent waiting for the lock counts towards the timeout of the deployment.
+	assert.WithinDuration(t, start.Add(time.Second), client.waitDeadline, 100*time.Millisecond)
+}
+
+func TestDeploymentResourceFindPreviousDeployment(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	deployment := func(envId, status string, planOnly bool, offset time.Duration) canyondp.Deployment {
+		return canyondp.Deployment{Id: uuid.New(), ProjectId: "my-project", EnvId: envId, Status: status, PlanOnly: planOnly, CreatedAt: createdAt.Add(offset)}
+	}
+	older := deployment("development", "succeeded", false, -3*time.Minute)
+	previous := deployment("development", "succeeded", false, -2*time.Minute)
+	failed := deployment("development", "failed", false, -time.Minute)
+	planOnly := deployment("development", "succeeded", true, -30*time.Second)
+	otherEnv := deployment("production", "succeeded", false, -10*time.Second)
+	// The deployment of the model was created a few milliseconds after the second its creation time is truncated to.
+	self := deployment("development", "succeeded", false, 500*time.Millisecond)
+	newer := deployment("development", "succeeded", false, time.Minute)
+
+	data := testDeploymentResourceModel(t, map[string]interface{}{
+		"project_id": "my-project",
+		"env_id":     "development",
+		"id":         self.Id.String(),
+		"created_at": createdAt.Format(time.RFC3339),
+	})
+
+	d := &DeploymentResource{dpClient: &fakeDeploymentClient{deployments: []canyondp.Deployment{older, previous, failed, planOnly, otherEnv, self, newer}}, orgId: "my-org"}
+	found, err := d.findPreviousDeployment(context.Background(), data)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, previous.Id, found.Id)
+
+	d = &DeploymentResource{dpClient: &fakeDeploymentClient{deployments: []canyondp.Deployment{failed, planOnly, otherEnv, self, newer}}, orgId: "my-org"}
+	found, err = d.findPreviousDeployment(context.Background(), data)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestDeploymentResourceWaitForDeployment_rollback(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		rollbackStatus string
+		expected       string
+	}{
+		{name: "succeeded", rollbackStatus: "succeeded", expected: "Rolled back to deployment %s in deployment"},
+		{name: "failed", rollbackStatus: "failed", expected: "Rollback to deployment %s failed: Deployment failed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			previous := canyondp.Deployment{
+				Id: uuid.New(), ProjectId: "my-project", EnvId: "development", Status: "succeeded", CreatedAt: time.Now().Add(-time.Minute),
+			}
+			client := &fakeDeploymentClient{deployments: []canyondp.Deployment{previous}, status: tc.rollbackStatus}
+			d := &DeploymentResource{dpClient: client, orgId: "my-org"}
+
+			data := testDeploymentResourceModel(t, map[string]interface{}{
+				"project_id":          "my-project",
+				"env_id":              "development",
+				"manifest":            testDeploymentManifest,
+				"mode":                "deploy",
+				"rollback_on_failure": true,
+			})
+			var diags diag.Diagnostics
+			outputsKey := d.doDeployment(context.Background(), &data, uuid.NewString(), &diags)
+			require.False(t, diags.HasError(), diags)
+			client.statuses = map[uuid.UUID]string{uuid.MustParse(data.Id.ValueString()): "failed"}
+			// The changes of the rollback deployment can not be read, which is only a warning.
+			client.diffStatus = http.StatusInternalServerError
+
+			recordedKey := d.waitForDeployment(context.Background(), &data, &diags, outputsKey)
+			require.Len(t, diags.Errors(), 1)
+			assert.Contains(t, diags.Errors()[0].Detail(), fmt.Sprintf(tc.expected, previous.Id))
+			require.Len(t, diags.Warnings(), 1)
+			assert.Contains(t, diags.Warnings()[0].Detail(), "Unable to read the changes of deployment")
+			require.Len(t, client.created, 2)
+			assert.Equal(t, previous.Id, *client.created[1].RollbackToDeploymentId)
+			if tc.rollbackStatus == "succeeded" {
+				// The model records the rollback deployment, whose outputs are encrypted for the returned key.
+				assert.Equal(t, client.deployments[2].Id.String(), data.Id.ValueString())
+				_, err := fetchDeploymentOutputs(context.Background(), client, "my-org", client.deployments[2].Id, recordedKey)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, outputsKey, recordedKey)
+			}
+		})
+	}
+}
+
+func TestDeploymentResourceUpdate_rollback(t *testing.T) {
+	completedAt := time.Now().Add(-time.Minute)
+	previous := canyondp.Deployment{
+		Id: uuid.New(), ProjectId: "my-project", EnvId: "development", Status: "succeeded", CreatedAt: completedAt.Add(-time.Minute), CompletedAt: &completedAt,
+		Manifest: canyondp.DeploymentManifest{Workloads: map[string]canyondp.DeploymentManifestWorkload{"main": {}}},
+	}
+	client := &fakeDeploymentClient{deployments: []canyondp.Deployment{previous}, status: "succeeded", failures: 1}
+	d := &DeploymentResource{dpClient: client, orgId: "my-org", locks: NewDeploymentLocks()}
+
+	previousManifest := `{"workloads":{"main":{}}}`
+	state := tfsdk.State{Schema: testDeploymentResourceSchema(), Raw: testDeploymentResourceValue(t, map[string]interface{}{
+		"project_id":          "my-project",
+		"env_id":              "development",
+		"manifest":            previousManifest,
+		"mode":                "deploy",
+		"rollback_on_failure": true,
+		"id":                  previous.Id.String(),
+		"created_at":          previous.CreatedAt.Format(time.RFC3339),
+		"status":              "succeeded",
+	})}
+	plan := tfsdk.Plan{Schema: state.Schema, Raw: testDeploymentResourceValue(t, map[string]interface{}{
+		"project_id":          "my-project",
+		"env_id":              "development",
+		"manifest":            testDeploymentManifest,
+		"mode":                "deploy",
+		"rollback_on_failure": true,
+	})}
+	response := fwresource.UpdateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw.Copy()}}
+	testInitPrivateState(&response.Private)
+	d.Update(context.Background(), fwresource.UpdateRequest{Plan: plan, State: state}, &response)
+	require.Len(t, response.Diagnostics.Errors(), 1)
+	assert.Contains(t, response.Diagnostics.Errors()[0].Detail(), fmt.Sprintf("Rolled back to deployment %s", previous.Id))
+	require.Len(t, client.created, 2)
+
+	// The state records the rollback deployment that the environment runs now, not the failed deployment.
+	var data DeploymentResourceModel
+	require.False(t, response.State.Get(context.Background(), &data).HasError())
+	assert.Equal(t, client.deployments[2].Id.String(), data.Id.ValueString())
+	assert.Equal(t, "succeeded", data.Status.ValueString())
+	assert.Equal(t, previous.Id.String(), data.RollbackToDeploymentId.ValueString())
+	assert.True(t, deploymentManifestsEqual(context.Background(), NewDeploymentManifestValue(previousManifest), data.Manifest))
+	// The private state holds the outputs key of the rollback deployment.
+	outputsKey, diags := getDeploymentOutputsKey(context.Background(), response.Private)
+	require.False(t, diags.HasError(), diags)
+	require.NotNil(t, outputsKey)
+	assert.Equal(t, client.outputsRecipients[client.deployments[2].Id], outputsKey.Recipient().String())
+	// A failed update does not taint the resource, so on_destroy still runs when it is destroyed.
+	createFailed, diags := getDeploymentCreateFailed(context.Background(), response.Private)
+	require.False(t, diags.HasError(), diags)
+	assert.False(t, createFailed)
+
+	// The next plan of the same configuration deploys the configured manifest again.
+	var planned DeploymentResourceModel
+	require.False(t, plan.Get(context.Background(), &planned).HasError())
+	assert.True(t, deploymentChanged(context.Background(), planned, data))
+}
+
+func TestDeploymentResourceCheckRemovalLimits(t *testing.T) {
+	diff := canyondp.DeploymentDiff{
+		NumRemoved: 2,
+		Changes: []canyondp.DeploymentDiffChange{
+			{Id: "a", Resource: "workload.main", Type: canyondp.DeploymentDiffChangeTypeParamsChanged, Summary: "params changed"},
+			{Id: "b", Resource: "postgres.db", Type: canyondp.DeploymentDiffChangeTypeRemoved, Summary: "removed"},
+			{Id: "c", Resource: "s3.bucket", Type: canyondp.DeploymentDiffChangeTypeRemoved, Summary: "removed"},
+		},
+	}
+	for _, tc := range []struct {
+		name             string
+		attributes       map[string]interface{}
+		removedResources int
+		expectedError    string
+		// expectedCreated lists whether each created deployment is plan only.
+		expectedCreated []bool
+	}{
+		{
+			name:            "nodes exceeded",
+			attributes:      map[string]interface{}{"max_removed_nodes": 1},
+			expectedError:   "would remove 2 resource nodes, more than max_removed_nodes (1):\n  - postgres.db: removed\n  - s3.bucket: removed",
+			expectedCreated: []bool{},
+		},
+		{
+			name:            "nodes within limit",
+			attributes:      map[string]interface{}{"max_removed_nodes": 2},
+			expectedCreated: []bool{false},
+		},
+		{
+			name:             "resources exceeded",
+			attributes:       map[string]interface{}{"max_removed_resources": 3},
+			removedResources: 4,
+			expectedError:    "would remove 4 Terraform resources, more than max_removed_resources (3)",
+			expectedCreated:  []bool{true},
+		},
+		{
+			name:             "resources within limit",
+			attributes:       map[string]interface{}{"max_removed_nodes": 2, "max_removed_resources": 3},
+			removedResources: 3,
+			expectedCreated:  []bool{true, false},
+		},
+		{
+			name:            "plan only",
+			attributes:      map[string]interface{}{"max_removed_nodes": 0, "max_removed_resources": 0, "mode": "plan_only"},
+			expectedCreated: []bool{false},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakeDeploymentClient{status: "succeeded", diff: diff, removedResources: ref.Ref(tc.removedResources)}
+			d := &DeploymentResource{dpClient: client, orgId: "my-org", locks: NewDeploymentLocks()}
+
+			attributes := map[string]interface{}{
+				"project_id": "my-project",
+				"env_id":     "development",
+				"manifest":   testDeploymentManifest,
+				"mode":       "deploy",
+			}
+			for name, value := range tc.attributes {
+				attributes[name] = value
+			}
+			plan := tfsdk.Plan{Schema: testDeploymentResourceSchema(), Raw: testDeploymentResourceValue(t, attributes)}
+			response := fwresource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw.Copy()}}
+			testInitPrivateState(&response.Private)
+			d.Create(context.Background(), fwresource.CreateRequest{Plan: plan}, &response)
+
+			if tc.expectedError != "" {
+				require.Len(t, response.Diagnostics.Errors(), 1)
+				assert.Contains(t, response.Diagnostics.Errors()[0].Detail(), tc.expectedError)
+				// The removed resource nodes are listed for both limits.
+				assert.Contains(t, response.Diagnostics.Errors()[0].Detail(), "  - postgres.db: removed\n  - s3.bucket: removed")
+			} else {
+				require.False(t, response.Diagnostics.HasError(), response.Diagnostics)
+			}
+			created := make([]bool, 0)
+			for _, body := range client.created {
+				created = append(created, body.PlanOnly != nil && *body.PlanOnly)
+			}
+			assert.Equal(t, tc.expectedCreated, created)
+		})
+	}
+}
+
+func TestDeploymentResourceDelete(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	older := canyondp.Deployment{
+		Id: uuid.New(), ProjectId: "my-project", EnvId: "development", Status: "succeeded", CreatedAt: createdAt.Add(-time.Minute),
+		Manifest: canyondp.DeploymentManifest{Workloads: map[string]canyondp.DeploymentManifestWorkload{"old": {}}},
+	}
+	ours := canyondp.Deployment{Id: uuid.New(), ProjectId: "my-project", EnvId: "development", Status: "succeeded", CreatedAt: createdAt}
+
+	for _, tc := range []struct {
+		name         string
+		mode         string
+		onDestroy    string
+		deployments  []canyondp.Deployment
+		status       string
+		createFailed bool
+		expected     *canyondp.DeploymentManifest
+		warning      string
+	}{
+		{
+			name:        "rollback",
+			mode:        "deploy",
+			onDestroy:   deploymentOnDestroyRollback,
+			deployments: []canyondp.Deployment{older, ours},
+			expected:    &older.Manifest,
+		},
+		{
+			name:        "rollback without previous deployment",
+			mode:        "deploy",
+			onDestroy:   deploymentOnDestroyRollback,
+			deployments: []canyondp.Deployment{ours},
+			warning:     "nothing to roll back to",
+		},
+		{
+			name:        "empty",
+			mode:        "deploy",
+			onDestroy:   deploymentOnDestroyEmpty,
+			deployments: []canyondp.Deployment{older, ours},
+			expected:    &canyondp.DeploymentManifest{Workloads: map[string]canyondp.DeploymentManifestWorkload{}},
+		},
+		{
+			name:        "none",
+			mode:        "deploy",
+			onDestroy:   deploymentOnDestroyNone,
+			deployments: []canyondp.Deployment{older, ours},
+		},
+		{
+			name:        "plan only",
+			mode:        "plan_only",
+			onDestroy:   deploymentOnDestroyEmpty,
+			deployments: []canyondp.Deployment{older, ours},
+		},
+		{
+			name:         "failed create",
+			mode:         "deploy",
+			onDestroy:    deploymentOnDestroyEmpty,
+			deployments:  []canyondp.Deployment{older, ours},
+			status:       "failed",
+			createFailed: true,
+			warning:      "on_destroy 'empty' is not deployed",
+		},
+		{
+			name:         "rolled back after failed create",
+			mode:         "deploy",
+			onDestroy:    deploymentOnDestroyRollback,
+			deployments:  []canyondp.Deployment{older, ours},
+			status:       "succeeded",
+			createFailed: true,
+			warning:      "on_destroy 'rollback' is not deployed",
+		},
+		{
+			name:        "failed update",
+			mode:        "deploy",
+			onDestroy:   deploymentOnDestroyEmpty,
+			deployments: []canyondp.Deployment{older, ours},
+			status:      "failed",
+			expected:    &canyondp.DeploymentManifest{Workloads: map[string]canyondp.DeploymentManifestWorkload{}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakeDeploymentClient{deployments: tc.deployments, status: "succeeded"}
+			d := &DeploymentResource{dpClient: client, orgId: "my-org", locks: NewDeploymentLocks()}
+
+			attributes := map[string]interface{}{
+				"project_id": "my-project",
+				"env_id":     "development",
+				"manifest":   testDeploymentManifest,
+				"mode":       tc.mode,
+				"on_destroy": tc.onDestroy,
+				"id":         ours.Id.String(),
+				"created_at": createdAt.Format(time.RFC3339),
+			}
+			if tc.status != "" {
+				attributes["status"] = tc.status
+				attributes["completed_at"] = createdAt.Add(time.Minute).Format(time.RFC3339)
+			}
+			state := tfsdk.State{Schema: testDeploymentResourceSchema(), Raw: testDeploymentResourceValue(t, attributes)}
+			require.False(t, state.SetAttribute(context.Background(), path.Root("timeouts").AtName("delete"), "10m").HasError())
+			request := fwresource.DeleteRequest{State: state}
+			// A failed create taints the resource, and the next apply destroys it before creating it again.
+			testInitPrivateState(&request.Private)
+			require.False(t, setDeploymentCreateFailed(context.Background(), request.Private, tc.createFailed).HasError())
+			response := fwresource.DeleteResponse{State: state}
+			d.Delete(context.Background(), request, &response)
+			require.False(t, response.Diagnostics.HasError(), response.Diagnostics)
+
+			if tc.warning != "" {
+				require.Len(t, response.Diagnostics.Warnings(), 1)
+				assert.Contains(t, response.Diagnostics.Warnings()[0].Detail(), tc.warning)
+			}
+			if tc.expected == nil {
+				assert.Empty(t, client.created)
+				return
+			}
+			require.Len(t, client.created, 1)
+			assert.Equal(t, canyondp.Deploy, client.created[0].Mode)
+			assert.Equal(t, *tc.expected, *client.created[0].Manifest)
+			// The teardown deployment is waited for within the delete timeout.
+			assert.NotNil(t, client.deployments[len(client.deployments)-1].CompletedAt)
+			assert.WithinDuration(t, time.Now().Add(10*time.Minute), client.waitDeadline, time.Minute)
+		})
+	}
+}
+
+func TestDeploymentResourceImportState(t *testing.T) {
+	for _, mode := range []string{"deploy", "plan_only"} {
+		t.Run(mode, func(t *testing.T) {
+			completedAt := time.Now().Add(-time.Minute)
+			deployment := canyondp.Deployment{
+				Id: uuid.New(), ProjectId: "my-project", EnvId: "development", Mode: "deploy", PlanOnly: mode == "plan_only",
+				Status: "succeeded", RunnerId: "my-runner", CreatedAt: completedAt.Add(-time.Minute), CompletedAt: &completedAt,
+			}
+			require.NoError(t, json.Unmarshal([]byte(testDeploymentManifest), &deployment.Manifest))
+			client := &fakeDeploymentClient{deployments: []canyondp.Deployment{deployment}}
+			d := &DeploymentResource{dpClient: client, orgId: "my-org"}
+
+			schema := testDeploymentResourceSchema()
+			importResponse := fwresource.ImportStateResponse{State: tfsdk.State{Schema: schema, Raw: tftypes.NewValue(schema.Type().TerraformType(context.Background()), nil)}}
+			d.ImportState(context.Background(), fwresource.ImportStateRequest{ID: deployment.Id.String()}, &importResponse)
+			require.False(t, importResponse.Diagnostics.HasError(), importResponse.Diagnostics)
+
+			readRequest := fwresource.ReadRequest{State: importResponse.State}
+			testInitPrivateState(&readRequest.Private)
+			readResponse := fwresource.ReadResponse{State: importResponse.State}
+			d.Read(context.Background(), readRequest, &readResponse)
+			require.False(t, readResponse.Diagnostics.HasError(), readResponse.Diagnostics)
+
+			var data DeploymentResourceModel
+			require.False(t, readResponse.State.Get(context.Background(), &data).HasError())
+			assert.Equal(t, deployment.Id.String(), data.Id.ValueString())
+			assert.Equal(t, "my-project", data.ProjectId.ValueString())
+			assert.Equal(t, "development", data.EnvId.ValueString())
+			assert.Equal(t, mode, data.Mode.ValueString())
+			assert.Equal(t, "my-runner", data.RunnerId.ValueString())
+			assert.Equal(t, deployment.CreatedAt.Format(time.RFC3339), data.CreatedAt.ValueString())
+			assert.Equal(t, "succeeded", data.Status.ValueString())
+			assert.False(t, json.Valid([]byte(data.Manifest.ValueString())), "the imported manifest is YAML")
+			assert.YAMLEq(t, testDeploymentManifest, data.Manifest.ValueString())
+
+			// A configuration matching the imported deployment keeps it instead of deploying again.
+			plan := tfsdk.Plan{Schema: schema, Raw: testDeploymentResourceValue(t, map[string]interface{}{
+				"project_id":   "my-project",
+				"env_id":       "development",
+				"manifest":     testDeploymentManifest,
+				"mode":         mode,
+				"drift_policy": deploymentDriftPolicyIgnore,
+			})}
+			planResponse := fwresource.ModifyPlanResponse{Plan: plan}
+			d.ModifyPlan(context.Background(), fwresource.ModifyPlanRequest{Plan: plan, State: readResponse.State}, &planResponse)
+			require.False(t, planResponse.Diagnostics.HasError(), planResponse.Diagnostics)
+			var planned DeploymentResourceModel
+			require.False(t, planResponse.Plan.Get(context.Background(), &planned).HasError())
+			assert.Equal(t, deployment.Id.String(), planned.Id.ValueString())
+			assert.Empty(t, client.created)
+		})
+	}
+}
+
+func TestDeploymentChanged(t *testing.T) {
+	deploymentId := uuid.NewString()
+	state := testDeploymentResourceModel(t, map[string]interface{}{
+		"project_id": "my-project",
+		"env_id":     "development",
+		"manifest":   testDeploymentManifest,
+		"mode":       "deploy",
+		"id":         deploymentId,
+		"status":     "succeeded",
+	})
+	plan := func(attributes map[string]interface{}) DeploymentResourceModel {
+		all := map[string]interface{}{
+			"project_id": "my-project",
+			"env_id":     "development",
+			"manifest":   testDeploymentManifest,
+			"mode":       "deploy",
+		}
+		for name, value := range attributes {
+			all[name] = value
+		}
+		return testDeploymentResourceModel(t, all)
+	}
+
+	// Only the runner log level changed.
+	logLevelOnly := plan(map[string]interface{}{"runner_log_level": "debug"})
+	assert.False(t, deploymentChanged(context.Background(), logLevelOnly, state))
+	copyDeploymentResult(&logLevelOnly, state)
+	assert.Equal(t, deploymentId, logLevelOnly.Id.ValueString())
+	assert.Equal(t, "succeeded", logLevelOnly.Status.ValueString())
+	assert.Equal(t, "debug", logLevelOnly.RunnerLogLevel.ValueString())
+
+	// The same manifest encoded as YAML instead of JSON.
+	yamlManifest := plan(map[string]interface{}{"manifest": "workloads:\n  main:\n    variables:\n      ANIMAL: cat\n"})
+	assert.False(t, deploymentChanged(context.Background(), yamlManifest, state))
+
+	assert.True(t, deploymentChanged(context.Background(), plan(map[string]interface{}{"manifest": `{"workloads":{"main":{"variables":{"ANIMAL":"dog"}}}}`}), state))
+	assert.True(t, deploymentChanged(context.Background(), plan(map[string]interface{}{"mode": "plan_only"}), state))
+	assert.True(t, deploymentChanged(context.Background(), plan(map[string]interface{}{"rollback_to_deployment_id": uuid.NewString()}), state))
+}
+
+func TestDeploymentResourceRead_wait_on_refresh(t *testing.T) {
+	for _, waitOnRefresh := range []bool{true, false} {
+		t.Run(fmt.Sprint(waitOnRefresh), func(t *testing.T) {
+			deploymentId := uuid.New()
+			client := &fakeDeploymentClient{
+				status: "succeeded",
+				deployments: []canyondp.Deployment{
+					{Id: deploymentId, ProjectId: "my-project", EnvId: "development", Status: "in_progress", CreatedAt: time.Now()},
+				},
+			}
+			d := &DeploymentResource{dpClient: client, orgId: "my-org"}
+
+			state := tfsdk.State{Schema: testDeploymentResourceSchema(), Raw: testDeploymentResourceValue(t, map[string]interface{}{
+				"project_id":      "my-project",
+				"env_id":          "development",
+				"manifest":        testDeploymentManifest,
+				"mode":            "deploy",
+				"id":              deploymentId.String(),
+				"status":          "in_progress",
+				"wait_on_refresh": waitOnRefresh,
+				"drift_policy":    "ignore",
+			})}
+			response := fwresource.ReadResponse{State: state}
+			d.Read(context.Background(), fwresource.ReadRequest{State: state}, &response)
+			require.False(t, response.Diagnostics.HasError(), response.Diagnostics)
+
+			var data DeploymentResourceModel
+			require.False(t, response.State.Get(context.Background(), &data).HasError())
+			if waitOnRefresh {
+				assert.Equal(t, "succeeded", data.Status.ValueString())
+				assert.False(t, data.CompletedAt.IsNull())
+				assert.Empty(t, response.Diagnostics.Warnings())
+			} else {
+				assert.Equal(t, "in_progress", data.Status.ValueString())
+				assert.True(t, data.CompletedAt.IsNull())
+				require.Len(t, response.Diagnostics.Warnings(), 1)
+				assert.Equal(t, "Deployment not completed", response.Diagnostics.Warnings()[0].Summary())
+			}
+		})
+	}
+}
+
+func TestDeploymentResourceCheckDeploymentDrift(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	completedAt := createdAt.Add(time.Second)
+	ours := canyondp.Deployment{Id: uuid.New(), ProjectId: "my-project", EnvId: "development", Status: "succeeded", CreatedAt: createdAt, CompletedAt: &completedAt}
+	newer := canyondp.Deployment{
+		Id: uuid.New(), ProjectId: "my-project", EnvId: "development", Status: "succeeded", CreatedAt: createdAt.Add(time.Minute), CompletedAt: &completedAt,
+		Manifest: canyondp.DeploymentManifest{Workloads: map[string]canyondp.DeploymentManifestWorkload{"other": {}}},
+	}
+	model := func(policy string) DeploymentResourceModel {
+		return testDeploymentResourceModel(t, map[string]interface{}{
+			"project_id":                "my-project",
+			"env_id":                    "development",
+			"manifest":                  testDeploymentManifest,
+			"rollback_to_deployment_id": uuid.NewString(),
+			"id":                        ours.Id.String(),
+			"created_at":                createdAt.Format(time.RFC3339),
+			"drift_policy":              policy,
+		})
+	}
+
+	t.Run("no newer deployment", func(t *testing.T) {
+		d := &DeploymentResource{dpClient: &fakeDeploymentClient{deployments: []canyondp.Deployment{ours}}, orgId: "my-org"}
+		data := model(deploymentDriftPolicyWarn)
+		var diags diag.Diagnostics
+		d.checkDeploymentDrift(context.Background(), &data, &diags)
+		assert.Empty(t, diags)
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		d := &DeploymentResource{dpClient: &fakeDeploymentClient{deployments: []canyondp.Deployment{ours, newer}}, orgId: "my-org"}
+		data := model(deploymentDriftPolicyWarn)
+		var diags diag.Diagnostics
+		d.checkDeploymentDrift(context.Background(), &data, &diags)
+		require.Len(t, diags.Warnings(), 1)
+		assert.Equal(t, "Deployment drift detected", diags.Warnings()[0].Summary())
+		assert.Contains(t, diags.Warnings()[0].Detail(), newer.Id.String())
+		assert.JSONEq(t, testDeploymentManifest, data.Manifest.ValueString())
+	})
+
+	t.Run("redeploy", func(t *testing.T) {
+		d := &DeploymentResource{dpClient: &fakeDeploymentClient{deployments: []canyondp.Deployment{ours, newer}}, orgId: "my-org"}
+		data := model(deploymentDriftPolicyRedeploy)
+		var diags diag.Diagnostics
+		d.checkDeploymentDrift(context.Background(), &data, &diags)
+		assert.Empty(t, diags)
+		assert.Equal(t, "workloads:\n    other: {}\n", data.Manifest.ValueString())
+		assert.True(t, data.RollbackToDeploymentId.IsNull())
+	})
+
+	t.Run("ignore on read", func(t *testing.T) {
+		d := &DeploymentResource{dpClient: &fakeDeploymentClient{deployments: []canyondp.Deployment{ours, newer}}, orgId: "my-org"}
+		state := tfsdk.State{Schema: testDeploymentResourceSchema(), Raw: testDeploymentResourceValue(t, map[string]interface{}{
+			"project_id":   "my-project",
+			"env_id":       "development",
+			"manifest":     testDeploymentManifest,
+			"mode":         "deploy",
+			"id":           ours.Id.String(),
+			"created_at":   createdAt.Format(time.RFC3339),
+			"drift_policy": deploymentDriftPolicyIgnore,
+		})}
+		response := fwresource.ReadResponse{State: state}
+		d.Read(context.Background(), fwresource.ReadRequest{State: state}, &response)
+		assert.Empty(t, response.Diagnostics)
+	})
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+)
+
+// defaultDeploymentTfFilename is the name of the compiled TF file when the API does not suggest one.
+const defaultDeploymentTfFilename = "main.tf"
+
+var _ datasource.DataSource = &DeploymentTfDataSource{}
+
+func NewDeploymentTfDataSource() datasource.DataSource {
+	return &DeploymentTfDataSource{}
+}
+
+type DeploymentTfDataSource struct {
+	dpClient canyondp.ClientWithResponsesInterface
+	orgId    string
+}
+
+type DeploymentTfDataSourceModel struct {
+	DeploymentId types.String `tfsdk:"deployment_id"`
+	Files        types.Map    `tfsdk:"files"`
+}
+
+func (d *DeploymentTfDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment_tf"
+}
+
+func (d *DeploymentTfDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deployment TF data source. Returns the OpenTofu configuration the Platform Orchestrator compiled and ran for a deployment, for debugging and auditing.",
+
+		Attributes: map[string]schema.Attribute{
+			"deployment_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the deployment.",
+				Required:            true,
+			},
+			"files": schema.MapAttribute{
+				MarkdownDescription: "The compiled TF files of the deployment, keyed by filename. The contents of inline modules are not included.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *DeploymentTfDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*HumanitecProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			HUM_PROVIDER_ERR,
+			fmt.Sprintf("Expected *HumanitecProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.dpClient = providerData.DpClient
+	d.orgId = providerData.OrgId
+}
+
+func (d *DeploymentTfDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DeploymentTfDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deploymentUuid, err := uuid.Parse(data.DeploymentId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("deployment_id"), HUM_INPUT_ERR, fmt.Sprintf("The deployment ID must be a UUID, got error: %s", err))
+		return
+	}
+
+	httpResp, err := d.dpClient.GetDeploymentTfWithResponse(ctx, d.orgId, deploymentUuid)
+	if err != nil {
+		resp.Diagnostics.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to read deployment TF, got error: %s", err))
+		return
+	}
+
+	if httpResp.StatusCode() == http.StatusNotFound {
+		resp.Diagnostics.AddError(HUM_RESOURCE_NOT_FOUND_ERR, fmt.Sprintf("TF of deployment with ID %s not found in org %s", data.DeploymentId.ValueString(), d.orgId))
+		return
+	}
+
+	if httpResp.StatusCode() != http.StatusOK {
+		resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to read deployment TF, unexpected status code: %d, body: %s", httpResp.StatusCode(), httpResp.Body))
+		return
+	}
+
+	files := map[string]string{
+		deploymentTfFilename(httpResp.HTTPResponse.Header.Get("Content-Disposition")): string(httpResp.Body),
+	}
+	filesValue, diags := types.MapValueFrom(ctx, types.StringType, files)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Files = filesValue
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// deploymentTfFilename returns the filename suggested by the Content-Disposition header, or the default filename.
+func deploymentTfFilename(contentDisposition string) string {
+	if _, params, err := mime.ParseMediaType(contentDisposition); err == nil {
+		if name := filepath.Base(params["filename"]); params["filename"] != "" && name != "." && name != "/" {
+			return name
+		}
+	}
+	return defaultDeploymentTfFilename
+}
@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccDeploymentTfDataSource_not_found(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "platform-orchestrator_deployment_tf" "tf" {
+  deployment_id = "00000000-0000-0000-0000-000000000000"
+}
+`, ExpectError: regexp.MustCompile(`TF of deployment with ID 00000000-0000-0000-0000-000000000000 not found`),
+			},
+		},
+	})
+}
+
+func TestDeploymentTfFilename(t *testing.T) {
+	assert.Equal(t, "deployment.tf", deploymentTfFilename(`attachment; filename="deployment.tf"`))
+	assert.Equal(t, "main.tf", deploymentTfFilename(`attachment; filename="../../main.tf"`))
+	assert.Equal(t, defaultDeploymentTfFilename, deploymentTfFilename(""))
+	assert.Equal(t, defaultDeploymentTfFilename, deploymentTfFilename("attachment"))
+}
@@ -0,0 +1,312 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+)
+
+var _ datasource.DataSource = &DeploymentsDataSource{}
+
+func NewDeploymentsDataSource() datasource.DataSource {
+	return &DeploymentsDataSource{}
+}
+
+type DeploymentsDataSource struct {
+	dpClient canyondp.ClientWithResponsesInterface
+	orgId    string
+}
+
+type DeploymentsDataSourceModel struct {
+	ProjectId   types.String `tfsdk:"project_id"`
+	EnvId       types.String `tfsdk:"env_id"`
+	Status      types.String `tfsdk:"status"`
+	Mode        types.String `tfsdk:"mode"`
+	MaxResults  types.Int64  `tfsdk:"max_results"`
+	Deployments types.List   `tfsdk:"deployments"`
+}
+
+// DeploymentSummaryModel describes a deployment in a list of deployments.
+type DeploymentSummaryModel struct {
+	Id                     types.String `tfsdk:"id"`
+	ProjectId              types.String `tfsdk:"project_id"`
+	EnvId                  types.String `tfsdk:"env_id"`
+	Status                 types.String `tfsdk:"status"`
+	StatusMessage          types.String `tfsdk:"status_message"`
+	Mode                   types.String `tfsdk:"mode"`
+	RollbackToDeploymentId types.String `tfsdk:"rollback_to_deployment_id"`
+	CreatedBy              types.String `tfsdk:"created_by"`
+	CreatedAt              types.String `tfsdk:"created_at"`
+	CompletedAt            types.String `tfsdk:"completed_at"`
+	Metrics                types.Object `tfsdk:"metrics"`
+}
+
+func (d *DeploymentsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployments"
+}
+
+// deploymentMetricsDataSourceAttribute returns the data source schema of the metrics of a deployment.
+func deploymentMetricsDataSourceAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "The metrics of the deployment. The Terraform resource counts are only known once the deployment has completed.",
+		Computed:            true,
+		Attributes: map[string]schema.Attribute{
+			"num_workloads": schema.Int64Attribute{
+				MarkdownDescription: "The number of workloads in the deployment.",
+				Computed:            true,
+			},
+			"num_resource_nodes": schema.Int64Attribute{
+				MarkdownDescription: "The number of resource nodes in the resource graph.",
+				Computed:            true,
+			},
+			"num_tf_resources": schema.Int64Attribute{
+				MarkdownDescription: "The number of Terraform resources.",
+				Computed:            true,
+			},
+			"num_tf_resources_added": schema.Int64Attribute{
+				MarkdownDescription: "The number of Terraform resources added.",
+				Computed:            true,
+			},
+			"num_tf_resources_changed": schema.Int64Attribute{
+				MarkdownDescription: "The number of Terraform resources changed.",
+				Computed:            true,
+			},
+			"num_tf_resources_removed": schema.Int64Attribute{
+				MarkdownDescription: "The number of Terraform resources removed.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func deploymentSummaryDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			MarkdownDescription: "The ID of the deployment.",
+			Computed:            true,
+		},
+		"project_id": schema.StringAttribute{
+			MarkdownDescription: "The ID of the project of the deployment.",
+			Computed:            true,
+		},
+		"env_id": schema.StringAttribute{
+			MarkdownDescription: "The ID of the environment of the deployment.",
+			Computed:            true,
+		},
+		"status": schema.StringAttribute{
+			MarkdownDescription: "The status of the deployment.",
+			Computed:            true,
+		},
+		"status_message": schema.StringAttribute{
+			MarkdownDescription: "The message associated with the status of the deployment.",
+			Computed:            true,
+		},
+		"mode": schema.StringAttribute{
+			MarkdownDescription: "The mode of the deployment, for example 'deploy', 'plan_only', or 'rollback'.",
+			Computed:            true,
+		},
+		"rollback_to_deployment_id": schema.StringAttribute{
+			MarkdownDescription: "The ID of the deployment this deployment rolled back to, if it is a rollback.",
+			Computed:            true,
+		},
+		"created_by": schema.StringAttribute{
+			MarkdownDescription: "The ID of the user that created the deployment.",
+			Computed:            true,
+		},
+		"created_at": schema.StringAttribute{
+			MarkdownDescription: "The Created At timestamp of the deployment in RFC3339 format.",
+			Computed:            true,
+		},
+		"completed_at": schema.StringAttribute{
+			MarkdownDescription: "The Completed At timestamp of the deployment in RFC3339 format, if it has completed.",
+			Computed:            true,
+		},
+		"metrics": deploymentMetricsDataSourceAttribute(),
+	}
+}
+
+func deploymentSummaryAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":                        types.StringType,
+		"project_id":                types.StringType,
+		"env_id":                    types.StringType,
+		"status":                    types.StringType,
+		"status_message":            types.StringType,
+		"mode":                      types.StringType,
+		"rollback_to_deployment_id": types.StringType,
+		"created_by":                types.StringType,
+		"created_at":                types.StringType,
+		"completed_at":              types.StringType,
+		"metrics":                   types.ObjectType{AttrTypes: deploymentMetricsAttributeTypes()},
+	}
+}
+
+func toDeploymentSummaryModel(ctx context.Context, item canyondp.DeploymentSummary) (DeploymentSummaryModel, diag.Diagnostics) {
+	metrics, diags := toDeploymentMetricsValue(ctx, item.Metrics)
+	model := DeploymentSummaryModel{
+		Id:                     types.StringValue(item.Id.String()),
+		ProjectId:              types.StringValue(item.ProjectId),
+		EnvId:                  types.StringValue(item.EnvId),
+		Status:                 types.StringValue(item.Status),
+		StatusMessage:          types.StringValue(item.StatusMessage),
+		Mode:                   types.StringValue(deploymentMode(item.Mode, item.PlanOnly)),
+		RollbackToDeploymentId: types.StringNull(),
+		CreatedBy:              types.StringValue(item.CreatedBy.String()),
+		CreatedAt:              types.StringValue(item.CreatedAt.Format(time.RFC3339)),
+		CompletedAt:            types.StringNull(),
+		Metrics:                metrics,
+	}
+	if item.RollbackToDeploymentId != nil {
+		model.RollbackToDeploymentId = types.StringValue(item.RollbackToDeploymentId.String())
+	}
+	if item.CompletedAt != nil {
+		model.CompletedAt = types.StringValue(item.CompletedAt.Format(time.RFC3339))
+	}
+	return model, diags
+}
+
+func (d *DeploymentsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deployments data source",
+
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Only return deployments in this project.",
+				Optional:            true,
+			},
+			"env_id": schema.StringAttribute{
+				MarkdownDescription: "Only return deployments to environments with this ID.",
+				Optional:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only return deployments with this status, for example 'succeeded' or 'failed'.",
+				Optional:            true,
+			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "Only return deployments with this mode, for example 'deploy', 'plan_only', or 'rollback'.",
+				Optional:            true,
+			},
+			"max_results": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of the most recent matching deployments to return. By default, all matching deployments are returned.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"deployments": schema.ListNestedAttribute{
+				MarkdownDescription: "The list of deployments, most recent first.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: deploymentSummaryDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *DeploymentsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*HumanitecProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			HUM_PROVIDER_ERR,
+			fmt.Sprintf("Expected *HumanitecProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.dpClient = providerData.DpClient
+	d.orgId = providerData.OrgId
+}
+
+func (d *DeploymentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DeploymentsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The API does not guarantee an order, so collect the matching deployments of all pages and sort them before
+	// keeping the most recent ones.
+	var summaries []canyondp.DeploymentSummary
+	var pageCursor *string
+	for {
+		httpResp, err := d.dpClient.ListDeploymentsWithResponse(ctx, d.orgId, &canyondp.ListDeploymentsParams{
+			ProjectId: data.ProjectId.ValueStringPointer(),
+			EnvId:     data.EnvId.ValueStringPointer(),
+			Page:      pageCursor,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to list deployments, got error: %s", err))
+			return
+		}
+		if httpResp.StatusCode() != http.StatusOK {
+			resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to list deployments, unexpected status code: %d, body: %s", httpResp.StatusCode(), httpResp.Body))
+			return
+		}
+
+		for _, item := range httpResp.JSON200.Items {
+			if !data.Status.IsNull() && item.Status != data.Status.ValueString() {
+				continue
+			} else if !data.Mode.IsNull() && deploymentMode(item.Mode, item.PlanOnly) != data.Mode.ValueString() {
+				continue
+			}
+			summaries = append(summaries, item)
+		}
+		if httpResp.JSON200.NextPageToken == nil {
+			break
+		}
+		pageCursor = httpResp.JSON200.NextPageToken
+	}
+	slices.SortStableFunc(summaries, func(a, b canyondp.DeploymentSummary) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if maxResults := int(data.MaxResults.ValueInt64()); maxResults > 0 && len(summaries) > maxResults {
+		summaries = summaries[:maxResults]
+	}
+
+	deploymentAttributeTypes := deploymentSummaryAttributeTypes()
+	items := make([]attr.Value, 0, len(summaries))
+	for _, item := range summaries {
+		model, diags := toDeploymentSummaryModel(ctx, item)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if dm, diags := types.ObjectValueFrom(ctx, deploymentAttributeTypes, model); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		} else {
+			items = append(items, dm)
+		}
+	}
+
+	itemsValue, diags := types.ListValue(types.ObjectType{AttrTypes: deploymentAttributeTypes}, items)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	data.Deployments = itemsValue
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	canyondp "terraform-provider-humanitec-v2/internal/clients/canyon-dp"
+)
+
+func TestAccDeploymentsDataSource(t *testing.T) {
+	suffix := strings.ToLower(rand.Text())
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A new environment has no deployments yet
+			{
+				Config: fmt.Sprintf(`
+resource "platform-orchestrator_project" "project" {
+  id = "project-%[1]s"
+}
+
+resource "platform-orchestrator_environment_type" "env_type" {
+  id = "env-type-%[1]s"
+}
+
+resource "platform-orchestrator_environment" "env" {
+  id          = "env-%[1]s"
+  project_id  = platform-orchestrator_project.project.id
+  env_type_id = platform-orchestrator_environment_type.env_type.id
+}
+
+data "platform-orchestrator_deployments" "all" {
+  project_id  = platform-orchestrator_environment.env.project_id
+  env_id      = platform-orchestrator_environment.env.id
+  status      = "succeeded"
+  max_results = 5
+}
+`, suffix),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.platform-orchestrator_deployments.all",
+						tfjsonpath.New("deployments"),
+						knownvalue.ListExact([]knownvalue.Check{}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccDeploymentsDataSource_invalid_max_results(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "platform-orchestrator_deployments" "all" {
+  max_results = 0
+}
+`, ExpectError: regexp.MustCompile(`Attribute max_results value must be at least 1`),
+			},
+		},
+	})
+}
+
+func TestDeploymentsDataSourceRead(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	deployment := func(envId, status string, planOnly bool, offset time.Duration) canyondp.Deployment {
+		return canyondp.Deployment{Id: uuid.New(), ProjectId: "my-project", EnvId: envId, Mode: "deploy", Status: status, PlanOnly: planOnly, CreatedAt: createdAt.Add(offset)}
+	}
+	// The API returns the deployments in no particular order.
+	oldest := deployment("development", "succeeded", false, 0)
+	newest := deployment("development", "succeeded", false, 3*time.Minute)
+	failed := deployment("development", "failed", false, 4*time.Minute)
+	planOnly := deployment("development", "succeeded", true, 5*time.Minute)
+	middle := deployment("development", "succeeded", false, 2*time.Minute)
+	otherEnv := deployment("production", "succeeded", false, 6*time.Minute)
+	client := &fakeDeploymentClient{deployments: []canyondp.Deployment{oldest, newest, failed, planOnly, middle, otherEnv}, pageSize: 2}
+	d := &DeploymentsDataSource{dpClient: client, orgId: "my-org"}
+
+	for _, tc := range []struct {
+		name       string
+		attributes map[string]interface{}
+		expected   []canyondp.Deployment
+	}{
+		{
+			name:       "environment",
+			attributes: map[string]interface{}{"project_id": "my-project", "env_id": "development"},
+			expected:   []canyondp.Deployment{planOnly, failed, newest, middle, oldest},
+		},
+		{
+			name:       "status and mode",
+			attributes: map[string]interface{}{"project_id": "my-project", "status": "succeeded", "mode": "deploy"},
+			expected:   []canyondp.Deployment{otherEnv, newest, middle, oldest},
+		},
+		{
+			name:       "max_results",
+			attributes: map[string]interface{}{"env_id": "development", "status": "succeeded", "max_results": 2},
+			expected:   []canyondp.Deployment{planOnly, newest},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var schemaResponse fwdatasource.SchemaResponse
+			d.Schema(context.Background(), fwdatasource.SchemaRequest{}, &schemaResponse)
+			config := tfsdk.State{Schema: schemaResponse.Schema, Raw: tftypes.NewValue(schemaResponse.Schema.Type().TerraformType(context.Background()), nil)}
+			for name, value := range tc.attributes {
+				require.False(t, config.SetAttribute(context.Background(), path.Root(name), value).HasError(), name)
+			}
+
+			response := fwdatasource.ReadResponse{State: config}
+			d.Read(context.Background(), fwdatasource.ReadRequest{Config: tfsdk.Config{Schema: config.Schema, Raw: config.Raw}}, &response)
+			require.False(t, response.Diagnostics.HasError(), response.Diagnostics)
+
+			var data DeploymentsDataSourceModel
+			require.False(t, response.State.Get(context.Background(), &data).HasError())
+			var deployments []DeploymentSummaryModel
+			require.False(t, data.Deployments.ElementsAs(context.Background(), &deployments, false).HasError())
+			ids := make([]string, 0, len(deployments))
+			for _, item := range deployments {
+				ids = append(ids, item.Id.ValueString())
+			}
+			expected := make([]string, 0, len(tc.expected))
+			for _, item := range tc.expected {
+				expected = append(expected, item.Id.String())
+			}
+			assert.Equal(t, expected, ids)
+		})
+	}
+}
@@ -145,7 +145,13 @@ func (d *EnvironmentDataSource) Read(ctx context.Context, req datasource.ReadReq
 	}
 
 	// Convert API response to data source model
-	environment := *httpResp.JSON200
+	data = toEnvironmentDataSourceModel(*httpResp.JSON200)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func toEnvironmentDataSourceModel(environment canyoncp.Environment) EnvironmentDataSourceModel {
 	displayName := types.StringValue(environment.Id)
 	if environment.DisplayName != "" {
 		displayName = types.StringValue(environment.DisplayName)
@@ -161,7 +167,7 @@ func (d *EnvironmentDataSource) Read(ctx context.Context, req datasource.ReadReq
 		runnerId = types.StringValue(*environment.RunnerId)
 	}
 
-	data = EnvironmentDataSourceModel{
+	return EnvironmentDataSourceModel{
 		Id:            types.StringValue(environment.Id),
 		ProjectId:     types.StringValue(environment.ProjectId),
 		EnvTypeId:     types.StringValue(environment.EnvTypeId),
@@ -174,7 +180,4 @@ func (d *EnvironmentDataSource) Read(ctx context.Context, req datasource.ReadReq
 		RunnerId:      runnerId,
 		DeleteRules:   types.BoolValue(false),
 	}
-
-	// Save data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyoncp "terraform-provider-humanitec-v2/internal/clients/canyon-cp"
+)
+
+var _ datasource.DataSource = &EnvironmentsDataSource{}
+
+func NewEnvironmentsDataSource() datasource.DataSource {
+	return &EnvironmentsDataSource{}
+}
+
+type EnvironmentsDataSource struct {
+	cpClient canyoncp.ClientWithResponsesInterface
+	orgId    string
+}
+
+type EnvironmentsDataSourceModel struct {
+	ProjectId    types.String `tfsdk:"project_id"`
+	EnvTypeIds   types.Set    `tfsdk:"env_type_ids"`
+	Status       types.String `tfsdk:"status"`
+	Environments types.List   `tfsdk:"environments"`
+}
+
+// EnvironmentSummaryModel describes an environment in the list of environments. It has the attributes of the
+// environment data source, except for delete_rules, which only applies when deleting an environment.
+type EnvironmentSummaryModel struct {
+	Id            types.String `tfsdk:"id"`
+	ProjectId     types.String `tfsdk:"project_id"`
+	EnvTypeId     types.String `tfsdk:"env_type_id"`
+	DisplayName   types.String `tfsdk:"display_name"`
+	Uuid          types.String `tfsdk:"uuid"`
+	CreatedAt     types.String `tfsdk:"created_at"`
+	UpdatedAt     types.String `tfsdk:"updated_at"`
+	Status        types.String `tfsdk:"status"`
+	StatusMessage types.String `tfsdk:"status_message"`
+	RunnerId      types.String `tfsdk:"runner_id"`
+}
+
+func environmentSummaryAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":             types.StringType,
+		"project_id":     types.StringType,
+		"env_type_id":    types.StringType,
+		"display_name":   types.StringType,
+		"uuid":           types.StringType,
+		"created_at":     types.StringType,
+		"updated_at":     types.StringType,
+		"status":         types.StringType,
+		"status_message": types.StringType,
+		"runner_id":      types.StringType,
+	}
+}
+
+func (d *EnvironmentsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_environments"
+}
+
+func (d *EnvironmentsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Environments data source",
+
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Only return environments in this project. By default, the environments of all projects in the organization are returned.",
+				Optional:            true,
+			},
+			"env_type_ids": schema.SetAttribute{
+				MarkdownDescription: "Only return environments of these environment types.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only return environments with this status (active, deleting, delete_failed).",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(canyoncp.EnvironmentStatusActive),
+						string(canyoncp.EnvironmentStatusDeleting),
+						string(canyoncp.EnvironmentStatusDeleteFailed),
+					),
+				},
+			},
+			"environments": schema.ListNestedAttribute{
+				MarkdownDescription: "The list of environments.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The unique identifier for the Environment.",
+							Computed:            true,
+						},
+						"project_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the project this environment belongs to.",
+							Computed:            true,
+						},
+						"env_type_id": schema.StringAttribute{
+							MarkdownDescription: "The environment type for the environment.",
+							Computed:            true,
+						},
+						"display_name": schema.StringAttribute{
+							MarkdownDescription: "The display name of the Environment.",
+							Computed:            true,
+						},
+						"uuid": schema.StringAttribute{
+							MarkdownDescription: "The UUID of the Environment.",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "The date and time when the environment was created.",
+							Computed:            true,
+						},
+						"updated_at": schema.StringAttribute{
+							MarkdownDescription: "The date and time when the environment was updated.",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "The status of the environment (active, deleting, delete_failed).",
+							Computed:            true,
+						},
+						"status_message": schema.StringAttribute{
+							MarkdownDescription: "An optional message associated with the status.",
+							Computed:            true,
+						},
+						"runner_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the runner to be used to deploy this environment.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *EnvironmentsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*HumanitecProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			HUM_PROVIDER_ERR,
+			fmt.Sprintf("Expected *HumanitecProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.cpClient = providerData.CpClient
+	d.orgId = providerData.OrgId
+}
+
+// listEnvironmentsPage returns a page of the environments of the project, or of all projects in the organization if
+// the project ID is empty. The environment types are filtered by the API, the page cursor is nil for the first page.
+func (d *EnvironmentsDataSource) listEnvironmentsPage(ctx context.Context, projectId string, envTypeIds *[]string, pageCursor *string) (*canyoncp.EnvironmentPage, error) {
+	var page *canyoncp.EnvironmentPage
+	var statusCode int
+	var body []byte
+	if projectId != "" {
+		httpResp, err := d.cpClient.ListEnvironmentsWithResponse(ctx, d.orgId, projectId, &canyoncp.ListEnvironmentsParams{
+			Page:        pageCursor,
+			ByEnvTypeId: envTypeIds,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("got error: %w", err)
+		}
+		page, statusCode, body = httpResp.JSON200, httpResp.StatusCode(), httpResp.Body
+	} else {
+		httpResp, err := d.cpClient.ListEnvironmentsInOrgWithResponse(ctx, d.orgId, &canyoncp.ListEnvironmentsInOrgParams{
+			Page:        pageCursor,
+			ByEnvTypeId: envTypeIds,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("got error: %w", err)
+		}
+		page, statusCode, body = httpResp.JSON200, httpResp.StatusCode(), httpResp.Body
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d, body: %s", statusCode, body)
+	}
+	return page, nil
+}
+
+// toEnvironmentSummaryModel converts the environment the same way as the environment data source does.
+func toEnvironmentSummaryModel(environment canyoncp.Environment) EnvironmentSummaryModel {
+	model := toEnvironmentDataSourceModel(environment)
+	return EnvironmentSummaryModel{
+		Id:            model.Id,
+		ProjectId:     model.ProjectId,
+		EnvTypeId:     model.EnvTypeId,
+		DisplayName:   model.DisplayName,
+		Uuid:          model.Uuid,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
+		Status:        model.Status,
+		StatusMessage: model.StatusMessage,
+		RunnerId:      model.RunnerId,
+	}
+}
+
+func (d *EnvironmentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data EnvironmentsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var envTypeIds *[]string
+	if !data.EnvTypeIds.IsNull() {
+		var ids []string
+		resp.Diagnostics.Append(data.EnvTypeIds.ElementsAs(ctx, &ids, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		envTypeIds = &ids
+	}
+
+	environmentAttributeTypes := environmentSummaryAttributeTypes()
+
+	var items []attr.Value
+	var pageCursor *string
+	for {
+		page, err := d.listEnvironmentsPage(ctx, data.ProjectId.ValueString(), envTypeIds, pageCursor)
+		if err != nil {
+			resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to list environments, %s", err))
+			return
+		}
+
+		for _, item := range page.Items {
+			if !data.Status.IsNull() && string(item.Status) != data.Status.ValueString() {
+				continue
+			}
+			if em, diags := types.ObjectValueFrom(ctx, environmentAttributeTypes, toEnvironmentSummaryModel(item)); diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			} else {
+				items = append(items, em)
+			}
+		}
+		if page.NextPageToken == nil {
+			break
+		}
+		pageCursor = page.NextPageToken
+	}
+
+	itemsValue, diags := types.ListValue(types.ObjectType{AttrTypes: environmentAttributeTypes}, items)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	data.Environments = itemsValue
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
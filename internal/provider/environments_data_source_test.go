@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccEnvironmentsDataSource(t *testing.T) {
+	suffix := strings.ToLower(rand.Text())
+
+	cfg1 := fmt.Sprintf(`
+resource "platform-orchestrator_project" "project" {
+  id = "project-%[1]s"
+}
+
+resource "platform-orchestrator_environment_type" "development" {
+  id = "development-%[1]s"
+}
+
+resource "platform-orchestrator_environment_type" "production" {
+  id = "production-%[1]s"
+}
+
+resource "platform-orchestrator_environment" "development" {
+  id          = "development"
+  project_id  = platform-orchestrator_project.project.id
+  env_type_id = platform-orchestrator_environment_type.development.id
+}
+
+resource "platform-orchestrator_environment" "production" {
+  id          = "production"
+  project_id  = platform-orchestrator_project.project.id
+  env_type_id = platform-orchestrator_environment_type.production.id
+}
+`, suffix)
+	cfg2 := cfg1 + `
+data "platform-orchestrator_environments" "project" {
+  project_id = platform-orchestrator_project.project.id
+  status     = "active"
+}
+
+data "platform-orchestrator_environments" "production" {
+  env_type_ids = [platform-orchestrator_environment_type.production.id]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// First create the environments
+			{
+				Config: cfg1,
+			},
+			// Read testing
+			{
+				Config: cfg2,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.platform-orchestrator_environments.project",
+						tfjsonpath.New("environments"),
+						knownvalue.ListSizeExact(2),
+					),
+					statecheck.ExpectKnownValue(
+						"data.platform-orchestrator_environments.production",
+						tfjsonpath.New("environments"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"id":          knownvalue.StringExact("production"),
+								"project_id":  knownvalue.StringExact("project-" + suffix),
+								"env_type_id": knownvalue.StringExact("production-" + suffix),
+							}),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccEnvironmentsDataSource_invalid_status(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "platform-orchestrator_environments" "all" {
+  status = "deleted"
+}
+`, ExpectError: regexp.MustCompile(`Attribute status value must be one of`),
+			},
+		},
+	})
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyoncp "terraform-provider-humanitec-v2/internal/clients/canyon-cp"
+)
+
+var _ datasource.DataSource = &ModuleRulesDataSource{}
+
+func NewModuleRulesDataSource() datasource.DataSource {
+	return &ModuleRulesDataSource{}
+}
+
+type ModuleRulesDataSource struct {
+	cpClient canyoncp.ClientWithResponsesInterface
+	orgId    string
+}
+
+type ModuleRulesDataSourceModel struct {
+	ResourceType types.String `tfsdk:"resource_type"`
+	ModuleId     types.String `tfsdk:"module_id"`
+	ProjectId    types.String `tfsdk:"project_id"`
+	EnvTypeId    types.String `tfsdk:"env_type_id"`
+	EnvId        types.String `tfsdk:"env_id"`
+	Rules        types.List   `tfsdk:"rules"`
+}
+
+// ModuleRuleSummaryModel describes a module rule in the list of module rules.
+type ModuleRuleSummaryModel struct {
+	Id            types.String `tfsdk:"id"`
+	ModuleId      types.String `tfsdk:"module_id"`
+	ResourceType  types.String `tfsdk:"resource_type"`
+	ResourceClass types.String `tfsdk:"resource_class"`
+	ResourceId    types.String `tfsdk:"resource_id"`
+	ProjectId     types.String `tfsdk:"project_id"`
+	EnvTypeId     types.String `tfsdk:"env_type_id"`
+	EnvId         types.String `tfsdk:"env_id"`
+	CreatedAt     types.String `tfsdk:"created_at"`
+}
+
+func moduleRuleSummaryAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":             types.StringType,
+		"module_id":      types.StringType,
+		"resource_type":  types.StringType,
+		"resource_class": types.StringType,
+		"resource_id":    types.StringType,
+		"project_id":     types.StringType,
+		"env_type_id":    types.StringType,
+		"env_id":         types.StringType,
+		"created_at":     types.StringType,
+	}
+}
+
+func (d *ModuleRulesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module_rules"
+}
+
+func (d *ModuleRulesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Module Rules data source. Lists the module rules of the organization.",
+
+		Attributes: map[string]schema.Attribute{
+			"resource_type": schema.StringAttribute{
+				MarkdownDescription: "Only return rules matching this resource type.",
+				Optional:            true,
+			},
+			"module_id": schema.StringAttribute{
+				MarkdownDescription: "Only return rules pointing at this module.",
+				Optional:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Only return rules set to exactly this project id. Rules without a project id are not returned.",
+				Optional:            true,
+			},
+			"env_type_id": schema.StringAttribute{
+				MarkdownDescription: "Only return rules set to exactly this environment type. Rules without an environment type are not returned.",
+				Optional:            true,
+			},
+			"env_id": schema.StringAttribute{
+				MarkdownDescription: "Only return rules set to exactly this environment id. Rules without an environment id are not returned.",
+				Optional:            true,
+			},
+			"rules": schema.ListNestedAttribute{
+				MarkdownDescription: "The list of module rules.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The unique identifier for the Module Rule.",
+							Computed:            true,
+						},
+						"module_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the Module this rule applies to.",
+							Computed:            true,
+						},
+						"resource_type": schema.StringAttribute{
+							MarkdownDescription: "The resource type matched by this rule.",
+							Computed:            true,
+						},
+						"resource_class": schema.StringAttribute{
+							MarkdownDescription: "A resource class requested by the resource graph.",
+							Computed:            true,
+						},
+						"resource_id": schema.StringAttribute{
+							MarkdownDescription: "A specific resource id requested by the resource graph.",
+							Computed:            true,
+						},
+						"project_id": schema.StringAttribute{
+							MarkdownDescription: "The project id that this rule matches.",
+							Computed:            true,
+						},
+						"env_type_id": schema.StringAttribute{
+							MarkdownDescription: "The environment type to match this rule.",
+							Computed:            true,
+						},
+						"env_id": schema.StringAttribute{
+							MarkdownDescription: "The environment id to match this rule.",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "The date and time when the rule was created.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ModuleRulesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*HumanitecProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			HUM_PROVIDER_ERR,
+			fmt.Sprintf("Expected *HumanitecProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.cpClient = providerData.CpClient
+	d.orgId = providerData.OrgId
+}
+
+func toModuleRuleSummaryModel(item canyoncp.RuleSummary) ModuleRuleSummaryModel {
+	return ModuleRuleSummaryModel{
+		Id:            types.StringValue(item.Id.String()),
+		ModuleId:      types.StringValue(item.ModuleId),
+		ResourceType:  types.StringValue(item.ResourceType),
+		ResourceClass: types.StringValue(item.ResourceClass),
+		ResourceId:    toStringValueOrNil(item.ResourceId),
+		ProjectId:     toStringValueOrNil(item.ProjectId),
+		EnvTypeId:     toStringValueOrNil(item.EnvTypeId),
+		EnvId:         toStringValueOrNil(item.EnvId),
+		CreatedAt:     types.StringValue(item.CreatedAt.Format(time.RFC3339)),
+	}
+}
+
+func (d *ModuleRulesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ModuleRulesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ruleAttributeTypes := moduleRuleSummaryAttributeTypes()
+
+	var items []attr.Value
+	var pageCursor *string
+	for {
+		httpResp, err := d.cpClient.ListModuleRulesInOrgWithResponse(ctx, d.orgId, &canyoncp.ListModuleRulesInOrgParams{
+			Page:           pageCursor,
+			ByResourceType: data.ResourceType.ValueStringPointer(),
+			ByModuleId:     data.ModuleId.ValueStringPointer(),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to list module rules, got error: %s", err))
+			return
+		}
+		if httpResp.StatusCode() != http.StatusOK {
+			resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to list module rules, unexpected status code: %d, body: %s", httpResp.StatusCode(), httpResp.Body))
+			return
+		}
+
+		for _, item := range httpResp.JSON200.Items {
+			model := toModuleRuleSummaryModel(item)
+			// Only rules set to exactly these values are returned, not the more general rules that would also match them.
+			if !data.ProjectId.IsNull() && !model.ProjectId.Equal(data.ProjectId) {
+				continue
+			} else if !data.EnvTypeId.IsNull() && !model.EnvTypeId.Equal(data.EnvTypeId) {
+				continue
+			} else if !data.EnvId.IsNull() && !model.EnvId.Equal(data.EnvId) {
+				continue
+			}
+			if rm, diags := types.ObjectValueFrom(ctx, ruleAttributeTypes, model); diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			} else {
+				items = append(items, rm)
+			}
+		}
+		if httpResp.JSON200.NextPageToken == nil {
+			break
+		}
+		pageCursor = httpResp.JSON200.NextPageToken
+	}
+
+	itemsValue, diags := types.ListValue(types.ObjectType{AttrTypes: ruleAttributeTypes}, items)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	data.Rules = itemsValue
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccModuleRulesDataSource(t *testing.T) {
+	var (
+		moduleId       = fmt.Sprintf("test-module-%d", time.Now().UnixNano())
+		envTypeId      = fmt.Sprintf("test-env-type-%d", time.Now().UnixNano())
+		resourceTypeId = fmt.Sprintf("custom-type-%d", time.Now().UnixNano())
+	)
+
+	cfg1 := `
+resource "platform-orchestrator_resource_type" "custom_type" {
+  id            = "` + resourceTypeId + `"
+  output_schema = "{}"
+}
+
+resource "platform-orchestrator_environment_type" "test" {
+  id = "` + envTypeId + `"
+}
+
+resource "platform-orchestrator_module" "test" {
+  id            = "` + moduleId + `"
+  resource_type = platform-orchestrator_resource_type.custom_type.id
+  module_source = "s3://my-bucket/module.zip"
+}
+
+resource "platform-orchestrator_module_rule" "default" {
+  module_id = platform-orchestrator_module.test.id
+}
+
+resource "platform-orchestrator_module_rule" "env_type" {
+  module_id      = platform-orchestrator_module.test.id
+  resource_class = "custom-class"
+  env_type_id    = platform-orchestrator_environment_type.test.id
+}
+`
+	cfg2 := cfg1 + `
+data "platform-orchestrator_module_rules" "module" {
+  module_id = platform-orchestrator_module.test.id
+}
+
+data "platform-orchestrator_module_rules" "env_type" {
+  resource_type = platform-orchestrator_resource_type.custom_type.id
+  env_type_id   = platform-orchestrator_environment_type.test.id
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// First create the module rules
+			{
+				Config: cfg1,
+			},
+			// Read testing
+			{
+				Config: cfg2,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.platform-orchestrator_module_rules.module",
+						tfjsonpath.New("rules"),
+						knownvalue.ListSizeExact(2),
+					),
+					statecheck.ExpectKnownValue(
+						"data.platform-orchestrator_module_rules.env_type",
+						tfjsonpath.New("rules"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"module_id":      knownvalue.StringExact(moduleId),
+								"resource_type":  knownvalue.StringExact(resourceTypeId),
+								"resource_class": knownvalue.StringExact("custom-class"),
+								"resource_id":    knownvalue.Null(),
+								"project_id":     knownvalue.Null(),
+								"env_type_id":    knownvalue.StringExact(envTypeId),
+								"env_id":         knownvalue.Null(),
+							}),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
@@ -71,6 +71,9 @@ type HumanitecProviderData struct {
 
 	CpClient canyoncp.ClientWithResponsesInterface
 	DpClient canyondp.ClientWithResponsesInterface
+
+	// DeploymentLocks queues the deployments made to the same environment.
+	DeploymentLocks *DeploymentLocks
 }
 
 func (p *HumanitecProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -276,9 +279,10 @@ func (p *HumanitecProvider) Configure(ctx context.Context, req provider.Configur
 	}
 
 	respData := &HumanitecProviderData{
-		OrgId:    orgId,
-		CpClient: cpc,
-		DpClient: dpc,
+		OrgId:           orgId,
+		CpClient:        cpc,
+		DpClient:        dpc,
+		DeploymentLocks: NewDeploymentLocks(),
 	}
 
 	resp.DataSourceData = respData
@@ -324,6 +328,16 @@ func (p *HumanitecProvider) DataSources(ctx context.Context) []func() datasource
 		NewModuleRuleDataSource,
 		NewRunnerRuleDataSource,
 		NewEnvironmentDataSource,
+		NewEnvironmentsDataSource,
+		NewDeploymentsDataSource,
+		NewDeploymentDataSource,
+		NewDeploymentDiffDataSource,
+		NewActiveResourcesDataSource,
+		NewDeploymentTfDataSource,
+		NewDeploymentLogsDataSource,
+		NewRunnersDataSource,
+		NewModuleRulesDataSource,
+		NewRunnerRulesDataSource,
 	}
 }
 
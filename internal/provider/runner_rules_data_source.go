@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyoncp "terraform-provider-humanitec-v2/internal/clients/canyon-cp"
+)
+
+var _ datasource.DataSource = &RunnerRulesDataSource{}
+
+func NewRunnerRulesDataSource() datasource.DataSource {
+	return &RunnerRulesDataSource{}
+}
+
+type RunnerRulesDataSource struct {
+	cpClient canyoncp.ClientWithResponsesInterface
+	orgId    string
+}
+
+type RunnerRulesDataSourceModel struct {
+	RunnerId  types.String `tfsdk:"runner_id"`
+	ProjectId types.String `tfsdk:"project_id"`
+	EnvTypeId types.String `tfsdk:"env_type_id"`
+	Rules     types.List   `tfsdk:"rules"`
+}
+
+// RunnerRuleSummaryModel describes a runner rule in the list of runner rules.
+type RunnerRuleSummaryModel struct {
+	Id        types.String `tfsdk:"id"`
+	RunnerId  types.String `tfsdk:"runner_id"`
+	ProjectId types.String `tfsdk:"project_id"`
+	EnvTypeId types.String `tfsdk:"env_type_id"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+func runnerRuleSummaryAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":          types.StringType,
+		"runner_id":   types.StringType,
+		"project_id":  types.StringType,
+		"env_type_id": types.StringType,
+		"created_at":  types.StringType,
+	}
+}
+
+func (d *RunnerRulesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_runner_rules"
+}
+
+func (d *RunnerRulesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runner Rules data source. Lists the runner rules of the organization. Runner rules do not match on environment ids, so there is no `env_id` filter.",
+
+		Attributes: map[string]schema.Attribute{
+			"runner_id": schema.StringAttribute{
+				MarkdownDescription: "Only return rules pointing at this runner.",
+				Optional:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Only return rules set to exactly this project id. Rules without a project id are not returned.",
+				Optional:            true,
+			},
+			"env_type_id": schema.StringAttribute{
+				MarkdownDescription: "Only return rules set to exactly this environment type. Rules without an environment type are not returned.",
+				Optional:            true,
+			},
+			"rules": schema.ListNestedAttribute{
+				MarkdownDescription: "The list of runner rules.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The unique identifier for the Runner Rule.",
+							Computed:            true,
+						},
+						"runner_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the Runner this rule applies to.",
+							Computed:            true,
+						},
+						"project_id": schema.StringAttribute{
+							MarkdownDescription: "The project id that this rule matches.",
+							Computed:            true,
+						},
+						"env_type_id": schema.StringAttribute{
+							MarkdownDescription: "The environment type to match this rule.",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "The date and time when the rule was created.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RunnerRulesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*HumanitecProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			HUM_PROVIDER_ERR,
+			fmt.Sprintf("Expected *HumanitecProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.cpClient = providerData.CpClient
+	d.orgId = providerData.OrgId
+}
+
+func toRunnerRuleSummaryModel(item canyoncp.RunnerRuleSummary) RunnerRuleSummaryModel {
+	return RunnerRuleSummaryModel{
+		Id:        types.StringValue(item.Id.String()),
+		RunnerId:  types.StringValue(item.RunnerId),
+		ProjectId: types.StringValue(item.ProjectId),
+		EnvTypeId: types.StringValue(item.EnvTypeId),
+		CreatedAt: types.StringValue(item.CreatedAt.Format(time.RFC3339)),
+	}
+}
+
+func (d *RunnerRulesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RunnerRulesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ruleAttributeTypes := runnerRuleSummaryAttributeTypes()
+
+	var items []attr.Value
+	var pageCursor *string
+	for {
+		httpResp, err := d.cpClient.ListRunnerRulesInOrgWithResponse(ctx, d.orgId, &canyoncp.ListRunnerRulesInOrgParams{
+			Page:       pageCursor,
+			ByRunnerId: data.RunnerId.ValueStringPointer(),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to list runner rules, got error: %s", err))
+			return
+		}
+		if httpResp.StatusCode() != http.StatusOK {
+			resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to list runner rules, unexpected status code: %d, body: %s", httpResp.StatusCode(), httpResp.Body))
+			return
+		}
+
+		for _, item := range httpResp.JSON200.Items {
+			// Only rules set to exactly these values are returned, not the more general rules that would also match them.
+			if !data.ProjectId.IsNull() && item.ProjectId != data.ProjectId.ValueString() {
+				continue
+			} else if !data.EnvTypeId.IsNull() && item.EnvTypeId != data.EnvTypeId.ValueString() {
+				continue
+			}
+			if rm, diags := types.ObjectValueFrom(ctx, ruleAttributeTypes, toRunnerRuleSummaryModel(item)); diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			} else {
+				items = append(items, rm)
+			}
+		}
+		if httpResp.JSON200.NextPageToken == nil {
+			break
+		}
+		pageCursor = httpResp.JSON200.NextPageToken
+	}
+
+	itemsValue, diags := types.ListValue(types.ObjectType{AttrTypes: ruleAttributeTypes}, items)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	data.Rules = itemsValue
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
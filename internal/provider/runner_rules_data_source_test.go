@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccRunnerRulesDataSource(t *testing.T) {
+	var (
+		runnerId  = fmt.Sprintf("test-runner-%d", time.Now().UnixNano())
+		envTypeId = fmt.Sprintf("test-env-type-%d", time.Now().UnixNano())
+	)
+
+	cfg1 := `
+resource "platform-orchestrator_environment_type" "test" {
+  id = "` + envTypeId + `"
+}
+
+resource "platform-orchestrator_kubernetes_agent_runner" "test" {
+  id = "` + runnerId + `"
+  runner_configuration = {
+    key = <<EOT
+-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEAc5dgCx4ano39JT0XgTsHnts3jej+5xl7ZAwSIrKpef0=
+-----END PUBLIC KEY-----
+EOT
+    job = {
+      namespace       = "default"
+      service_account = "humanitec-runner"
+    }
+  }
+  state_storage_configuration = {
+    type = "kubernetes"
+    kubernetes_configuration = {
+      namespace = "humanitec-runner"
+    }
+  }
+}
+
+resource "platform-orchestrator_runner_rule" "default" {
+  runner_id = platform-orchestrator_kubernetes_agent_runner.test.id
+}
+
+resource "platform-orchestrator_runner_rule" "env_type" {
+  runner_id   = platform-orchestrator_kubernetes_agent_runner.test.id
+  env_type_id = platform-orchestrator_environment_type.test.id
+}
+`
+	cfg2 := cfg1 + `
+data "platform-orchestrator_runner_rules" "runner" {
+  runner_id = platform-orchestrator_kubernetes_agent_runner.test.id
+}
+
+data "platform-orchestrator_runner_rules" "env_type" {
+  env_type_id = platform-orchestrator_environment_type.test.id
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// First create the runner rules
+			{
+				Config: cfg1,
+			},
+			// Read testing
+			{
+				Config: cfg2,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.platform-orchestrator_runner_rules.runner",
+						tfjsonpath.New("rules"),
+						knownvalue.ListSizeExact(2),
+					),
+					statecheck.ExpectKnownValue(
+						"data.platform-orchestrator_runner_rules.env_type",
+						tfjsonpath.New("rules"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"runner_id":   knownvalue.StringExact(runnerId),
+								"project_id":  knownvalue.StringExact(""),
+								"env_type_id": knownvalue.StringExact(envTypeId),
+							}),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	canyoncp "terraform-provider-humanitec-v2/internal/clients/canyon-cp"
+)
+
+var _ datasource.DataSource = &RunnersDataSource{}
+
+func NewRunnersDataSource() datasource.DataSource {
+	return &RunnersDataSource{}
+}
+
+type RunnersDataSource struct {
+	cpClient canyoncp.ClientWithResponsesInterface
+	orgId    string
+}
+
+type RunnersDataSourceModel struct {
+	RunnerType       types.String `tfsdk:"runner_type"`
+	StateStorageType types.String `tfsdk:"state_storage_type"`
+	Runners          types.List   `tfsdk:"runners"`
+}
+
+// RunnerSummaryModel describes a runner in the list of runners.
+type RunnerSummaryModel struct {
+	Id               types.String `tfsdk:"id"`
+	Type             types.String `tfsdk:"type"`
+	Description      types.String `tfsdk:"description"`
+	StateStorageType types.String `tfsdk:"state_storage_type"`
+	CreatedAt        types.String `tfsdk:"created_at"`
+	UpdatedAt        types.String `tfsdk:"updated_at"`
+}
+
+func runnerSummaryAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":                 types.StringType,
+		"type":               types.StringType,
+		"description":        types.StringType,
+		"state_storage_type": types.StringType,
+		"created_at":         types.StringType,
+		"updated_at":         types.StringType,
+	}
+}
+
+func (d *RunnersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_runners"
+}
+
+func (d *RunnersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runners data source. Lists the runners of the organization regardless of their type.",
+
+		Attributes: map[string]schema.Attribute{
+			"runner_type": schema.StringAttribute{
+				MarkdownDescription: "Only return runners of this type (kubernetes, kubernetes-agent, kubernetes-eks, kubernetes-gke, serverless-ecs).",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(canyoncp.RunnerTypeKubernetes),
+						string(canyoncp.RunnerTypeKubernetesAgent),
+						string(canyoncp.RunnerTypeKubernetesEks),
+						string(canyoncp.RunnerTypeKubernetesGke),
+						string(canyoncp.RunnerTypeServerlessEcs),
+					),
+				},
+			},
+			"state_storage_type": schema.StringAttribute{
+				MarkdownDescription: "Only return runners with this type of state storage (kubernetes, s3, gcs, azurerm).",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(canyoncp.StateStorageTypeKubernetes),
+						string(canyoncp.StateStorageTypeS3),
+						string(canyoncp.StateStorageTypeGcs),
+						string(canyoncp.StateStorageTypeAzurerm),
+					),
+				},
+			},
+			"runners": schema.ListNestedAttribute{
+				MarkdownDescription: "The list of runners.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The unique identifier for the Runner.",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The type of the Runner.",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "The description of the Runner.",
+							Computed:            true,
+						},
+						"state_storage_type": schema.StringAttribute{
+							MarkdownDescription: "The type of state storage configuration for the Runner.",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "The date and time when the Runner was created.",
+							Computed:            true,
+						},
+						"updated_at": schema.StringAttribute{
+							MarkdownDescription: "The date and time when the Runner was updated.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RunnersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*HumanitecProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			HUM_PROVIDER_ERR,
+			fmt.Sprintf("Expected *HumanitecProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.cpClient = providerData.CpClient
+	d.orgId = providerData.OrgId
+}
+
+func toRunnerSummaryModel(item canyoncp.RunnerSummary) RunnerSummaryModel {
+	model := RunnerSummaryModel{
+		Id:               types.StringValue(item.Id),
+		Type:             types.StringNull(),
+		Description:      toStringValueOrNil(item.Description),
+		StateStorageType: types.StringNull(),
+		CreatedAt:        types.StringValue(item.CreatedAt.Format(time.RFC3339)),
+		UpdatedAt:        types.StringValue(item.UpdatedAt.Format(time.RFC3339)),
+	}
+	if item.RunnerConfiguration != nil {
+		model.Type = types.StringValue(string(item.RunnerConfiguration.Type))
+	}
+	if item.StateStorageConfiguration != nil {
+		model.StateStorageType = types.StringValue(string(item.StateStorageConfiguration.Type))
+	}
+	return model
+}
+
+func (d *RunnersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RunnersDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	runnerAttributeTypes := runnerSummaryAttributeTypes()
+
+	var items []attr.Value
+	var pageCursor *string
+	for {
+		httpResp, err := d.cpClient.ListRunnersWithResponse(ctx, d.orgId, &canyoncp.ListRunnersParams{
+			Page:         pageCursor,
+			ByRunnerType: data.RunnerType.ValueStringPointer(),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(HUM_CLIENT_ERR, fmt.Sprintf("Unable to list runners, got error: %s", err))
+			return
+		}
+		if httpResp.StatusCode() != http.StatusOK {
+			resp.Diagnostics.AddError(HUM_API_ERR, fmt.Sprintf("Unable to list runners, unexpected status code: %d, body: %s", httpResp.StatusCode(), httpResp.Body))
+			return
+		}
+
+		for _, item := range httpResp.JSON200.Items {
+			model := toRunnerSummaryModel(item)
+			if !data.StateStorageType.IsNull() && !model.StateStorageType.Equal(data.StateStorageType) {
+				continue
+			}
+			if rm, diags := types.ObjectValueFrom(ctx, runnerAttributeTypes, model); diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			} else {
+				items = append(items, rm)
+			}
+		}
+		if httpResp.JSON200.NextPageToken == nil {
+			break
+		}
+		pageCursor = httpResp.JSON200.NextPageToken
+	}
+
+	itemsValue, diags := types.ListValue(types.ObjectType{AttrTypes: runnerAttributeTypes}, items)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	data.Runners = itemsValue
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccRunnersDataSource(t *testing.T) {
+	var runnerId = fmt.Sprint("runner-", time.Now().UnixNano())
+
+	cfg1 := fmt.Sprintf(`
+resource "platform-orchestrator_kubernetes_runner" "test" {
+  id          = "%[1]s"
+  description = "Test Kubernetes Runner for runners data source"
+  runner_configuration = {
+    cluster = {
+      cluster_data = {
+        certificate_authority_data = "certificate-authority-data"
+        server                     = "10.0.1:6443"
+      }
+      auth = {
+        service_account_token = "service-account-token"
+      }
+    }
+    job = {
+      namespace       = "default"
+      service_account = "humanitec-runner"
+    }
+  }
+  state_storage_configuration = {
+    type = "kubernetes"
+    kubernetes_configuration = {
+      namespace = "humanitec-runner"
+    }
+  }
+}
+`, runnerId)
+	cfg2 := cfg1 + `
+data "platform-orchestrator_runners" "kubernetes" {
+  runner_type        = "kubernetes"
+  state_storage_type = "kubernetes"
+}
+
+data "platform-orchestrator_runners" "s3" {
+  state_storage_type = "s3"
+}
+
+output "kubernetes_runner" {
+  value = one([for r in data.platform-orchestrator_runners.kubernetes.runners : r if r.id == platform-orchestrator_kubernetes_runner.test.id])
+}
+
+output "s3_runner_ids" {
+  value = [for r in data.platform-orchestrator_runners.s3.runners : r.id if r.id == platform-orchestrator_kubernetes_runner.test.id]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// First create the runner
+			{
+				Config: cfg1,
+			},
+			// Read testing
+			{
+				Config: cfg2,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue(
+						"kubernetes_runner",
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"id":                 knownvalue.StringExact(runnerId),
+							"type":               knownvalue.StringExact("kubernetes"),
+							"description":        knownvalue.StringExact("Test Kubernetes Runner for runners data source"),
+							"state_storage_type": knownvalue.StringExact("kubernetes"),
+							"created_at":         knownvalue.NotNull(),
+							"updated_at":         knownvalue.NotNull(),
+						}),
+					),
+					statecheck.ExpectKnownOutputValue(
+						"s3_runner_ids",
+						knownvalue.ListSizeExact(0),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccRunnersDataSource_invalid_runner_type(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "platform-orchestrator_runners" "all" {
+  runner_type = "docker"
+}
+`, ExpectError: regexp.MustCompile(`Attribute runner_type value must be one of`),
+			},
+		},
+	})
+}
@@ -26,6 +26,14 @@ func toStringValueOrNil(str *string) basetypes.StringValue {
 	return types.StringValue(*str)
 }
 
+// toInt64ValueOrNil returns an Int64Value that is null if the input int pointer is nil, otherwise it returns an Int64Value with the value of the int pointer.
+func toInt64ValueOrNil(i *int) basetypes.Int64Value {
+	if i == nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(*i))
+}
+
 // AttributeTypeFromResourceSchemaAttr returns the attribute type for the given schema attribute.
 func AttributeTypeFromResourceSchemaAttr(a schema.Attribute) (attr.Type, error) {
 	switch typed := a.(type) {